@@ -0,0 +1,292 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// binary returns the source for L's MarshalBinary and UnmarshalBinary
+// functions. After the header written by lang.NewEncoder, a value in a
+// non-terminal or product position is a uvarint tag: 0 for nil, 1 for
+// a reference to the shared subtree with the uvarint ID that follows,
+// and otherwise 2 plus the index of its production, product type, or
+// terminal in L.nodes, followed by its fields. Terminals are varints,
+// or strings if they're represented by strings, slices are prefixed
+// by their lengths, and optional fields by 0 if absent or else 1.
+//
+// Shared subtrees, which have fields, are numbered in the order that
+// they're completed.
+func (L lang) binary() string {
+	var b, enc, dec strings.Builder
+
+	for i, n := range L.nodes() {
+		tag := i + 2
+		fmt.Fprintf(&enc, "case %v:\n", n.name)
+		fmt.Fprintf(&dec, "case %v:\n", tag)
+		switch {
+		case n.term:
+			fmt.Fprintf(&enc, "w.Uint(%v)\n", tag)
+			L.binaryEncode(&enc, "n", nil, n.name)
+			fmt.Fprintf(&dec, "return %v\n", L.binaryDecode(nil, n.name))
+		case len(n.fields) == 0:
+			fmt.Fprintf(&enc, "w.Uint(%v)\n", tag)
+			fmt.Fprintf(&dec, "return %v{}\n", n.name)
+		default:
+			fmt.Fprintf(&enc, "if w.ref(n) {\nreturn\n}\nw.Uint(%v)\n", tag)
+			var fields []string
+			for _, field := range n.fields {
+				L.binaryEncode(&enc, "n."+field.Name(), field.Type(), "")
+				fields = append(fields, fmt.Sprintf("%v: %v", field.Name(), L.binaryDecode(field.Type(), "")))
+			}
+			fmt.Fprintf(&enc, "w.done(n)\n")
+			fmt.Fprintf(&dec, "return r.done(%v{%v})\n", n.name, strings.Join(fields, ", "))
+		}
+	}
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	fmt.Fprintf(&b, "import %q\n\n", langPath)
+
+	fmt.Fprintf(&b, `// MarshalBinary returns a compact binary encoding of x, which the
+// UnmarshalBinary functions of the same definition of %[1]v can read.
+// If share is set, subtrees that are Equal to earlier ones are encoded
+// as references to them, which makes the encoding smaller, but takes
+// longer, and the decoded subtrees share memory. Metadata isn't
+// encoded.
+func MarshalBinary(x Node, share bool) []byte {
+	w := binaryWriter{Encoder: lang.NewEncoder(Language, share)}
+	if share {
+		w.ids = make(map[uint64][]int)
+	}
+	w.node(x)
+	return w.Bytes()
+}
+
+`, L.name)
+
+	for _, defName := range keys(L.defs) {
+		if _, ok := L.defs[defName].(*nonterm); ok {
+			fmt.Fprintf(&b, "// UnmarshalBinary%v decodes a %v from data, which was written\n// by MarshalBinary. It fails if data is of a different definition\n// of %v.\n", defName, defName, L.name)
+			fmt.Fprintf(&b, "func UnmarshalBinary%v(data []byte) (%v, error) {\nreturn unmarshalBinary[%v](data, %q)\n}\n\n", defName, defName, defName, defName)
+		}
+	}
+
+	fmt.Fprintf(&b, `func unmarshalBinary[T Node](data []byte, want string) (T, error) {
+	r := binaryReader{Decoder: lang.NewDecoder(Language, data)}
+	x := binaryNode[T](&r, want)
+	if err := r.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+	return x, nil
+}
+
+type binaryWriter struct {
+	*lang.Encoder
+	ids   map[uint64][]int // IDs of shared subtrees by Hash, if sharing
+	nodes []Node           // shared subtrees by ID
+}
+
+// ref writes a reference to an earlier subtree that's Equal to x, if
+// sharing, and reports whether it did.
+func (w *binaryWriter) ref(x Node) bool {
+	if w.ids == nil {
+		return false
+	}
+	for _, id := range w.ids[Hash(x)] {
+		if Equal(w.nodes[id], x) {
+			w.Uint(1)
+			w.Uint(uint64(id))
+			return true
+		}
+	}
+	return false
+}
+
+// done records x as the next shared subtree, if sharing.
+func (w *binaryWriter) done(x Node) {
+	if w.ids != nil {
+		h := Hash(x)
+		w.ids[h] = append(w.ids[h], len(w.nodes))
+		w.nodes = append(w.nodes, x)
+	}
+}
+
+func (w *binaryWriter) node(x Node) {
+	switch n := x.(type) {
+	case nil:
+		w.Uint(0)
+%[1]v	}
+}
+
+type binaryReader struct {
+	*lang.Decoder
+	nodes []Node // shared subtrees by ID
+}
+
+// done records x as the next shared subtree, if the encoding shares
+// them, and returns it.
+func (r *binaryReader) done(x Node) Node {
+	if r.Shared() {
+		r.nodes = append(r.nodes, x)
+	}
+	return x
+}
+
+func (r *binaryReader) node() Node {
+	switch tag := r.Uint(); tag {
+	case 0:
+		return nil
+	case 1:
+		id := r.Uint()
+		if id >= uint64(len(r.nodes)) {
+			r.Failf("invalid reference %%d", id)
+			return nil
+		}
+		return r.nodes[id]
+%[2]v	default:
+		r.Failf("invalid tag %%d", tag)
+		return nil
+	}
+}
+
+// binaryNode reads a value, which must be a T.
+func binaryNode[T Node](r *binaryReader, want string) T {
+	x := r.node()
+	res, ok := x.(T)
+	if !ok && x != nil {
+		r.Failf("%%T does not belong to %%v", x, want)
+	}
+	return res
+}
+
+// binaryValid returns x, a terminal value, after checking that it's
+// valid.
+func binaryValid[T interface{ Valid() bool }](r *binaryReader, x T, want string) T {
+	if !x.Valid() {
+		r.Failf("invalid %%v %%v", want, x)
+	}
+	return x
+}
+
+func binarySlice[T any](r *binaryReader, f func() T) []T {
+	n := r.Len()
+	if n == 0 {
+		return nil
+	}
+	res := make([]T, n)
+	for i := range res {
+		res[i] = f()
+	}
+	return res
+}
+
+func binaryOpt[T any](r *binaryReader, f func() T) *T {
+	if r.Uint() == 0 {
+		return nil
+	}
+	res := f()
+	return &res
+}
+`, enc.String(), dec.String())
+
+	return b.String()
+}
+
+// termBasic returns the basic type underlying the representation of
+// the named terminal.
+func (L lang) termBasic(termName string) *types.Basic {
+	if t := L.defs[termName].(*term); t.repr != nil {
+		return t.repr.Underlying().(*types.Basic)
+	}
+	return types.Typ[types.Int]
+}
+
+// binaryEncode writes the statements that encode x, an expression of
+// type typ, with the binaryWriter w. If termName is non-empty, it
+// names the terminal to encode instead.
+func (L lang) binaryEncode(b *strings.Builder, x string, typ types.Type, termName string) {
+	if tparam, ok := typ.(*types.TypeParam); ok {
+		termName = tparam.Obj().Name()
+		if _, ok := L.defs[termName].(*term); !ok {
+			fmt.Fprintf(b, "w.node(%v)\n", x)
+			return
+		}
+	}
+	if termName != "" {
+		typ = L.termBasic(termName)
+	}
+
+	switch typ := typ.(type) {
+	case *types.Basic:
+		switch {
+		case typ.Info()&types.IsString != 0:
+			fmt.Fprintf(b, "w.String(string(%v))\n", x)
+		case typ.Info()&types.IsUnsigned != 0:
+			fmt.Fprintf(b, "w.Uint(uint64(%v))\n", x)
+		default:
+			fmt.Fprintf(b, "w.Int(int64(%v))\n", x)
+		}
+	case *types.Slice:
+		fmt.Fprintf(b, "w.Uint(uint64(len(%v)))\nfor _, x := range %v {\n", x, x)
+		L.binaryEncode(b, "x", typ.Elem(), "")
+		fmt.Fprintf(b, "}\n")
+	case *types.Pointer:
+		fmt.Fprintf(b, "if %v == nil {\nw.Uint(0)\n} else {\nw.Uint(1)\n", x)
+		L.binaryEncode(b, "*"+x, typ.Elem(), "")
+		fmt.Fprintf(b, "}\n")
+	default:
+		panic(fmt.Sprintf("unexpected field type %v", typ))
+	}
+}
+
+// binaryDecode returns an expression that decodes a value of type typ
+// with the binaryReader r. If termName is non-empty, it names the
+// terminal to decode instead.
+func (L lang) binaryDecode(typ types.Type, termName string) string {
+	if tparam, ok := typ.(*types.TypeParam); ok {
+		termName = tparam.Obj().Name()
+		if _, ok := L.defs[termName].(*term); !ok {
+			return fmt.Sprintf("binaryNode[%v](r, %q)", termName, termName)
+		}
+	}
+	if termName != "" {
+		x := fmt.Sprintf("%v(%v)", termName, binaryRead(L.termBasic(termName)))
+		if L.defs[termName].(*term).custom() {
+			x = fmt.Sprintf("binaryValid(r, %v, %q)", x, termName)
+		}
+		return x
+	}
+
+	switch typ := typ.(type) {
+	case *types.Basic:
+		return fmt.Sprintf("%v(%v)", typ, binaryRead(typ))
+	case *types.Slice:
+		return fmt.Sprintf("binarySlice(r, %v)", L.binaryFunc(typ.Elem()))
+	case *types.Pointer:
+		return fmt.Sprintf("binaryOpt(r, %v)", L.binaryFunc(typ.Elem()))
+	}
+	panic(fmt.Sprintf("unexpected field type %v", typ))
+}
+
+// binaryRead returns the call that reads a value of the basic type.
+func binaryRead(basic *types.Basic) string {
+	switch {
+	case basic.Info()&types.IsString != 0:
+		return "r.String()"
+	case basic.Info()&types.IsUnsigned != 0:
+		return "r.Uint()"
+	}
+	return "r.Int()"
+}
+
+// binaryFunc returns a function that decodes a value of type typ with
+// the binaryReader r.
+func (L lang) binaryFunc(typ types.Type) string {
+	return fmt.Sprintf("func() %v { return %v }", types.TypeString(typ, nil), L.binaryDecode(typ, ""))
+}
@@ -0,0 +1,598 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// A field wrapped as "bind[T]" holds binding occurrences of variables,
+// which are binders of the production that contains it, even if it's
+// within a product type. A field wrapped as "scope[T]" is within the
+// scope of its production's binders:
+//
+//	Lambda(Params bind[[]Symbol], Body scope[Expr])
+//	Let(Bindings []Binding, Body scope[Expr])
+//	LetRec(Bindings scope[[]Binding], Body scope[Expr])
+//	Binding interface{ struct{ Var bind[Symbol]; Val Expr } }
+//
+// A field wrapped as "bind[N]", for a non-terminal N, binds the
+// binders of its value instead, as if N's productions were product
+// types, and their scope fields are within the scope of the
+// production that contains it:
+//
+//	Closures(Closures scope[[]Closure], Body bind[LabelsBody])
+//	LabelsBody interface{ Labels(Bindings scope[[]RecBinding], Body scope[Expr]) }
+//
+// The wrappers are removed from the generated types, and recorded in
+// the language descriptor. The generated FreeVars, Subst, AlphaEqual,
+// and Uniquify functions follow them. All the binders of a language
+// must be of the same terminal, which is its variables.
+
+// A role is the part that a field plays in the binding structure of
+// its production.
+type role int
+
+const (
+	plain  role = iota
+	binds       // bind[T]
+	scoped      // scope[T]
+)
+
+var roleNames = [...]string{binds: "bind", scoped: "scope"}
+
+// roles records the roles of the fields unwrapped by unwrapField.
+var roles = make(map[*types.Var]role)
+
+// unwrapField returns field, with its type unwrapped and its role
+// recorded if it's wrapped as bind[T] or scope[T].
+func unwrapField(field *types.Var) *types.Var {
+	named, ok := field.Type().(*types.Named)
+	if !ok || !isKeyword(named, "bind", "scope") || named.TypeArgs().Len() != 1 {
+		return field
+	}
+	typ := named.TypeArgs().At(0)
+	var res *types.Var
+	if field.IsField() {
+		res = types.NewField(field.Pos(), field.Pkg(), field.Name(), typ, false)
+	} else {
+		res = types.NewParam(field.Pos(), field.Pkg(), field.Name(), typ)
+	}
+	roles[res] = binds
+	if named.Obj().Name() == "scope" {
+		roles[res] = scoped
+	}
+	return res
+}
+
+// unwrapCon returns con, with its fields unwrapped by unwrapField.
+func unwrapCon(con *types.Func) *types.Func {
+	sig := con.Type().(*types.Signature)
+	params := tupleVars(sig.Params())
+	changed := false
+	for i, param := range params {
+		params[i] = unwrapField(param)
+		changed = changed || params[i] != param
+	}
+	if !changed {
+		return con
+	}
+	sig = types.NewSignatureType(nil, nil, nil, types.NewTuple(params...), sig.Results(), sig.Variadic())
+	return types.NewFunc(con.Pos(), con.Pkg(), con.Name(), sig)
+}
+
+// unwrapStruct returns str, with its fields unwrapped by unwrapField.
+func unwrapStruct(str *types.Struct) *types.Struct {
+	fields := structFields(str)
+	changed := false
+	for i, field := range fields {
+		fields[i] = unwrapField(field)
+		changed = changed || fields[i] != field
+	}
+	if !changed {
+		return str
+	}
+	return types.NewStruct(fields, nil)
+}
+
+// fieldType returns the type of field as written in the language
+// declarations, including any role wrapper.
+func fieldType(field *types.Var) string {
+	typ := types.TypeString(field.Type(), nil)
+	if r := roles[field]; r != plain {
+		typ = roleNames[r] + "[" + typ + "]"
+	}
+	return typ
+}
+
+// checkBinders reports an error if one of L's binding fields doesn't
+// hold a terminal or non-terminal, or a slice or pointer thereof, if
+// the terminals don't all have the same type, or if a bound
+// non-terminal is used other than by binding fields. It returns the
+// name of that terminal, if there are any binding fields.
+func (L lang) checkBinders() string {
+	var vars string
+	var first *types.Var
+	bound := L.boundDefs()
+	for _, n := range L.nodes() {
+		for _, field := range n.fields {
+			tparam, _ := unptr(elemType(field.Type()))
+			if roles[field] != binds {
+				if tparam != nil && bound[tparam.Obj().Name()] {
+					errorf(field.Pos(), "field %v refers to %v, which is bound by other fields; it can only be used in binding fields", field.Name(), tparam.Obj().Name())
+				}
+				continue
+			}
+			if tparam == nil {
+				errorf(field.Pos(), "field %v binds %v, which is not a terminal or non-terminal", field.Name(), field.Type())
+				continue
+			}
+			if nt, ok := L.defs[tparam.Obj().Name()].(*nonterm); ok {
+				if len(nt.embeds) != 0 {
+					errorf(field.Pos(), "field %v binds %v, which embeds %v; a bound non-terminal can only have its own productions", field.Name(), tparam.Obj().Name(), keys(nt.embeds)[0])
+				}
+				continue
+			}
+			if _, ok := L.defs[tparam.Obj().Name()].(*term); !ok {
+				errorf(field.Pos(), "field %v binds %v, which is not a terminal or non-terminal", field.Name(), tparam.Obj().Name())
+				continue
+			}
+			switch name := tparam.Obj().Name(); {
+			case vars == "":
+				vars, first = name, field
+			case name != vars:
+				errorf(field.Pos(), "field %v binds %v, but field %v of %v binds %v; a language can only have one kind of variable", field.Name(), name, first.Name(), L.name, vars)
+			}
+		}
+	}
+	return vars
+}
+
+// boundDefs returns the names of the non-terminals of L that are
+// bound by binding fields.
+func (L lang) boundDefs() map[string]bool {
+	res := make(map[string]bool)
+	for _, n := range L.nodes() {
+		for _, field := range n.fields {
+			if roles[field] != binds {
+				continue
+			}
+			if tparam, _ := elemType(field.Type()).(*types.TypeParam); tparam != nil {
+				if _, ok := L.defs[tparam.Obj().Name()].(*nonterm); ok {
+					res[tparam.Obj().Name()] = true
+				}
+			}
+		}
+	}
+	return res
+}
+
+// elemType returns typ without any enclosing slices or pointers.
+func elemType(typ types.Type) types.Type {
+	for {
+		switch t := typ.(type) {
+		case *types.Slice:
+			typ = t.Elem()
+		case *types.Pointer:
+			typ = t.Elem()
+		default:
+			return typ
+		}
+	}
+}
+
+// bind returns the source for L's FreeVars, Subst, AlphaEqual, and
+// Uniquify functions. They're built on a renamer, which copies a
+// syntax tree while keeping track of the variables in scope.
+func (L lang) bind() string {
+	var b, bs, prods, cases strings.Builder
+
+	usesSlices := false
+	var products []string
+	bound := L.boundDefs()
+	for _, n := range L.nodes() {
+		if n.term || len(n.fields) == 0 {
+			continue
+		}
+		hasBinders := L.hasBinders(n.fields, make(map[string]bool))
+		if hasBinders {
+			fmt.Fprintf(&bs, "case %v:\n", n.name)
+			for _, field := range n.fields {
+				L.writeBinders(&bs, "n."+field.Name(), field.Type(), roles[field] == binds)
+			}
+			if n.product || bound[n.owner] {
+				products = append(products, n.name)
+			}
+		}
+
+		// A production's binders are in scope in its scope fields, and
+		// a product's, or a bound production's, in those of the
+		// production that contains it.
+		var cas strings.Builder
+		inner := "inner"
+		if !n.product && !bound[n.owner] {
+			if hasBinders {
+				fmt.Fprintf(&cas, "inner := r.enter(binders(n, nil), e)\n")
+			} else {
+				inner = "e"
+			}
+		}
+		for _, field := range n.fields {
+			x, e := "n."+field.Name(), "e"
+			if roles[field] == scoped {
+				e = inner
+			}
+			if y := L.renameExpr(x, field.Type(), roles[field] == binds, e, inner, &usesSlices); y != x {
+				fmt.Fprintf(&cas, "%v = %v\n", x, y)
+			}
+		}
+		if cas.Len() != 0 {
+			fmt.Fprintf(&cases, "case %v:\n%vreturn n\n", n.name, cas.String())
+		}
+	}
+	if len(products) != 0 {
+		fmt.Fprintf(&prods, "switch x.(type) {\ncase %v:\ninner = r.enter(binders(x, nil), e)\n}\n", strings.Join(products, ", "))
+	}
+
+	std := []string{"fmt", "maps"}
+	if usesSlices {
+		std = append(std, "slices")
+	}
+	t := L.defs[L.vars].(*term)
+	text := t.repr != nil && t.repr.Underlying().(*types.Basic).Info()&types.IsString != 0
+	if text {
+		std = append(std, "strconv", "strings")
+	}
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	b.WriteString(importDecl(std, nil))
+
+	fmt.Fprintf(&b, `// FreeVars returns the variables that occur free in x, in the order
+// of their first references.
+func FreeVars(x Node) []%[1]v {
+	var res []%[1]v
+	seen := make(map[%[1]v]bool)
+	r := &renamer{free: func(x %[1]v) {
+		if !seen[x] {
+			seen[x] = true
+			res = append(res, x)
+		}
+	}}
+	r.run(x, nil)
+	return res
+}
+
+// Subst returns a copy of x in which each free reference to a
+// variable in s is replaced by its mapping, renaming binders of x
+// that would capture a free variable of a replacement. It panics if a
+// replacement can't be stored where its variable was.
+func Subst[T Node](x T, s map[%[1]v]Node) T {
+	avoid := vars(x, nil)
+	e := make(env, len(s))
+	for y, n := range s {
+		if n == nil {
+			panic(fmt.Sprintf("Subst: nil replacement for %%v", y))
+		}
+		avoid[y] = true
+		vars(n, avoid)
+		free := make(map[%[1]v]bool)
+		for _, z := range FreeVars(n) {
+			free[z] = true
+		}
+		e[y] = &repl{n: n, free: free}
+	}
+	r := &renamer{rename: func(x %[1]v, inner env) (%[1]v, bool) {
+		for _, rp := range inner {
+			if rp != nil && rp.free[x] {
+				return fresh(x, avoid), true
+			}
+		}
+		return x, false
+	}}
+	return replaced(x, r.run(x, e))
+}
+
+// AlphaEqual reports whether a and b are equal up to the names of
+// their bound variables.
+func AlphaEqual(a, b Node) bool {
+	avoid := vars(b, vars(a, nil))
+	return Equal(canonical(a, avoid), canonical(b, avoid))
+}
+
+// canonical returns a copy of x in which each binder is renamed to
+// the next variable made by fresh, starting from the zero variable.
+// Trees canonicalized with the same avoid set, which holds all their
+// variables, are Equal if and only if they're alpha-equivalent.
+func canonical(x Node, avoid map[%[1]v]bool) Node {
+	avoid = maps.Clone(avoid)
+	r := &renamer{rename: func(%[1]v, env) (%[1]v, bool) {
+		var zero %[1]v
+		return fresh(zero, avoid), true
+	}}
+	return r.run(x, nil)
+}
+
+// Uniquify returns a copy of x in which binders are renamed so that
+// no two bind the same variable, and none binds a variable that also
+// occurs free.
+func Uniquify[T Node](x T) T {
+	avoid := vars(x, nil)
+	seen := make(map[%[1]v]bool)
+	for _, y := range FreeVars(x) {
+		seen[y] = true
+	}
+	r := &renamer{rename: func(x %[1]v, _ env) (%[1]v, bool) {
+		if seen[x] {
+			return fresh(x, avoid), true
+		}
+		seen[x] = true
+		return x, false
+	}}
+	return replaced(x, r.run(x, nil))
+}
+
+// vars adds every variable in x, whether bound, free, or binding, to
+// seen, which it allocates if it's nil, and returns seen.
+func vars(x Node, seen map[%[1]v]bool) map[%[1]v]bool {
+	if seen == nil {
+		seen = make(map[%[1]v]bool)
+	}
+	if x != nil {
+		Inspect(x, func(n Node) bool {
+			if v, ok := n.(%[1]v); ok {
+				seen[v] = true
+			}
+			return true
+		})
+	}
+	return seen
+}
+
+// binders appends the variables bound by x, a production or product
+// value, to res. A product's binders, like those of a bound
+// production, are bound by the production that contains it.
+func binders(x Node, res []%[1]v) []%[1]v {
+	switch n := x.(type) {
+%[2]v}
+	return res
+}
+
+// An env maps the variables in scope to their replacements. A binder
+// maps to its new name if it was renamed, and to nil if it wasn't.
+type env map[%[1]v]*repl
+
+// A repl is the replacement for a variable.
+type repl struct {
+	n    Node
+	free map[%[1]v]bool // variables free in n
+}
+
+// A renamer copies syntax trees, renaming binders and replacing
+// references to variables.
+type renamer struct {
+	// rename, if non-nil, returns the new name of the binder x, and
+	// whether it was renamed, given the environment within its
+	// production.
+	rename func(x %[1]v, inner env) (%[1]v, bool)
+
+	// free, if non-nil, is called for each reference to a variable
+	// that isn't in the environment.
+	free func(x %[1]v)
+}
+
+// run returns a copy of x with the replacements in e applied. If x is
+// a product value or a bound production, its binders are bound by x
+// itself.
+func (r *renamer) run(x Node, e env) Node {
+	inner := e
+	%[3]vreturn r.node(x, e, inner)
+}
+
+// enter returns the environment within a production that binds
+// vars, given the environment e outside it. Each binder shadows any
+// replacement for the same variable in e, and is renamed if r says
+// so.
+func (r *renamer) enter(vars []%[1]v, e env) env {
+	if len(vars) == 0 {
+		return e
+	}
+	inner := maps.Clone(e)
+	if inner == nil {
+		inner = make(env, len(vars))
+	}
+	for _, x := range vars {
+		inner[x] = nil
+	}
+	if r.rename == nil {
+		return inner
+	}
+	seen := make(map[%[1]v]bool, len(vars))
+	for _, x := range vars {
+		if seen[x] {
+			continue // bound twice by the same production
+		}
+		seen[x] = true
+		if y, ok := r.rename(x, inner); ok {
+			inner[x] = &repl{n: y, free: map[%[1]v]bool{y: true}}
+		}
+	}
+	return inner
+}
+
+// binder returns the new name of the binder x, given the environment
+// within its production.
+func (r *renamer) binder(x %[1]v, inner env) %[1]v {
+	if rp := inner[x]; rp != nil {
+		return rp.n.(%[1]v)
+	}
+	return x
+}
+
+// node returns a copy of x with the replacements in e applied, where
+// inner is the environment within the production that contains x.
+func (r *renamer) node(x Node, e, inner env) Node {
+	switch n := x.(type) {
+	case %[1]v:
+		rp, ok := e[n]
+		if !ok && r.free != nil {
+			r.free(n)
+		}
+		if rp != nil {
+			return rp.n
+		}
+		return n
+%[4]v}
+	return x
+}
+
+// renameNode returns a copy of x, as node does.
+func renameNode[T Node](r *renamer, x T, e, inner env) T {
+	if Node(x) == nil {
+		return x
+	}
+	return replaced(x, r.node(x, e, inner))
+}
+
+// replaced returns y, the replacement for x, as a T. It panics if y
+// isn't a T.
+func replaced[T Node](x T, y Node) T {
+	res, ok := y.(T)
+	if !ok && y != nil {
+		panic(fmt.Sprintf("Subst: cannot replace %%T with %%T", x, y))
+	}
+	return res
+}
+`, L.vars, bs.String(), prods.String(), cases.String())
+
+	if text {
+		fmt.Fprintf(&b, `
+// fresh returns a new variable based on x, which isn't in avoid, and
+// adds it to avoid. It replaces any numeric suffix ".N" of x.
+func fresh(x %[1]v, avoid map[%[1]v]bool) %[1]v {
+	base := string(x)
+	if i := strings.LastIndexByte(base, '.'); i >= 0 {
+		if _, err := strconv.Atoi(base[i+1:]); err == nil {
+			base = base[:i]
+		}
+	}
+	for n := 1; ; n++ {
+		x = %[1]v(base + "." + strconv.Itoa(n))
+		if !avoid[x] {
+			avoid[x] = true
+			return x
+		}
+	}
+}
+`, L.vars)
+	} else {
+		fmt.Fprintf(&b, `
+// fresh returns a new variable after x, which isn't in avoid, and
+// adds it to avoid.
+func fresh(x %[1]v, avoid map[%[1]v]bool) %[1]v {
+	for x++; avoid[x]; x++ {
+	}
+	avoid[x] = true
+	return x
+}
+`, L.vars)
+	}
+
+	return b.String()
+}
+
+// hasBinders reports whether fields bind any variables, either
+// themselves or within product values, where seen holds the products
+// already being checked.
+func (L lang) hasBinders(fields []*types.Var, seen map[string]bool) bool {
+	for _, field := range fields {
+		if roles[field] == binds {
+			return true
+		}
+		tparam, _ := elemType(field.Type()).(*types.TypeParam)
+		if tparam == nil || seen[tparam.Obj().Name()] {
+			continue
+		}
+		if nt, ok := L.defs[tparam.Obj().Name()].(*nonterm); ok && nt.str != nil {
+			seen[tparam.Obj().Name()] = true
+			if L.hasBinders(structFields(nt.str), seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeBinders writes the statements that append the binders within
+// x, an expression of type typ, to res: its variables or the binders
+// of its values, if it's a binding field, or else the binders of the
+// product values within it.
+func (L lang) writeBinders(b *strings.Builder, x string, typ types.Type, bind bool) {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		switch def := L.defs[typ.Obj().Name()].(type) {
+		case *term:
+			if bind {
+				fmt.Fprintf(b, "res = append(res, %v)\n", x)
+			}
+		case *nonterm:
+			if bind || def.str != nil && L.hasBinders(structFields(def.str), make(map[string]bool)) {
+				fmt.Fprintf(b, "res = binders(%v, res)\n", x)
+			}
+		}
+	case *types.Slice:
+		if elem, ok := typ.Elem().(*types.TypeParam); ok && bind {
+			if _, ok := L.defs[elem.Obj().Name()].(*term); ok {
+				fmt.Fprintf(b, "res = append(res, %v...)\n", x)
+				return
+			}
+		}
+		var elem strings.Builder
+		L.writeBinders(&elem, "x", typ.Elem(), bind)
+		if elem.Len() != 0 {
+			fmt.Fprintf(b, "for _, x := range %v {\n%v}\n", x, elem.String())
+		}
+	case *types.Pointer:
+		var elem strings.Builder
+		L.writeBinders(&elem, "*"+x, typ.Elem(), bind)
+		if elem.Len() != 0 {
+			fmt.Fprintf(b, "if %v != nil {\n%v}\n", x, elem.String())
+		}
+	}
+}
+
+// renameExpr returns an expression for a copy of x, an expression of
+// type typ, renamed by the renamer r: as binders, if bind is set, or
+// else with the replacements in the environment e applied, where
+// inner is the environment within the production that contains x.
+// It sets *usesSlices if the expression uses slices.Clone.
+func (L lang) renameExpr(x string, typ types.Type, bind bool, e, inner string, usesSlices *bool) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		if _, ok := L.defs[typ.Obj().Name()].(*term); ok {
+			switch {
+			case typ.Obj().Name() != L.vars:
+				return x
+			case bind:
+				return fmt.Sprintf("r.binder(%v, %v)", x, inner)
+			}
+		}
+		return fmt.Sprintf("renameNode(r, %v, %v, %v)", x, e, inner)
+	case *types.Slice:
+		elem := L.renameExpr("x", typ.Elem(), bind, e, inner, usesSlices)
+		if elem == "x" {
+			*usesSlices = true
+			return fmt.Sprintf("slices.Clone(%v)", x)
+		}
+		t := types.TypeString(typ.Elem(), nil)
+		return fmt.Sprintf("cloneSlice(%v, func(x %v) %v { return %v })", x, t, t, elem)
+	case *types.Pointer:
+		t := types.TypeString(typ.Elem(), nil)
+		return fmt.Sprintf("clonePtr(%v, func(x %v) %v { return %v })", x, t, t, L.renameExpr("x", typ.Elem(), bind, e, inner, usesSlices))
+	}
+	return x
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// check reports whether files are up to date on disk, printing a
+// unified diff for each file that differs. It also reports any stale
+// generated files, and any directories within base that don't
+// correspond to a language, such as the leftover package of a removed
+// language.
+func check(base string, files []file) bool {
+	ok := true
+
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		dirs[filepath.Dir(f.path)] = true
+
+		old, err := os.ReadFile(f.path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintln(os.Stderr, err)
+			ok = false
+			continue
+		}
+		if string(old) == string(f.src) {
+			continue
+		}
+		ok = false
+		if old == nil {
+			fmt.Printf("%v: missing\n", f.path)
+		}
+		fmt.Print(unified(f.path+" (on disk)", f.path+" (generated)", string(old), string(f.src)))
+	}
+
+	old, err := stale(files)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+	for _, path := range old {
+		fmt.Printf("%v: stale generated file\n", path)
+		ok = false
+	}
+
+	entries, err := os.ReadDir(base)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+	for _, entry := range entries {
+		if dir := filepath.Join(base, entry.Name()); entry.IsDir() && !dirs[dir] {
+			fmt.Printf("%v: stale directory does not correspond to any language\n", dir)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// generatedHeader is the first line of every generated file.
+const generatedHeader = "// Code generated by Hermes. DO NOT EDIT.\n"
+
+// stale returns the files in the directories of files that mklang
+// generated but that are no longer part of files, such as the bind.go
+// of a language that no longer binds variables. Only .go files that
+// start with generatedHeader are considered, so hand-written files,
+// like tests, are left alone.
+func stale(files []file) ([]string, error) {
+	dirs := make(map[string]bool)
+	current := make(map[string]bool)
+	for _, f := range files {
+		dirs[filepath.Dir(f.path)] = true
+		current[f.path] = true
+	}
+
+	var res []string
+	for _, dir := range keys(dirs) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if entry.IsDir() || filepath.Ext(path) != ".go" || current[path] {
+				continue
+			}
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if bytes.HasPrefix(src, []byte(generatedHeader)) {
+				res = append(res, path)
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestStale(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "L0")
+	if err := os.Mkdir(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	generated := generatedHeader + "\npackage L0\n"
+	for name, src := range map[string]string{
+		"L0.go":        generated,
+		"bind.go":      generated, // no longer generated
+		"meta.go":      generated, // no longer generated
+		"helper.go":    "package L0\n",
+		"L0_test.go":   "package L0\n",
+		"README":       generated,
+		"notes.go.txt": generated,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := []file{{filepath.Join(dir, "L0.go"), []byte(generated)}}
+	got, err := stale(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "bind.go"), filepath.Join(dir, "meta.go")}
+	if !slices.Equal(got, want) {
+		t.Errorf("stale = %q, want %q", got, want)
+	}
+	if check(base, files) {
+		t.Errorf("check reported stale files as up to date")
+	}
+
+	for _, path := range want {
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !check(base, files) {
+		t.Errorf("check reported up-to-date files as stale")
+	}
+}
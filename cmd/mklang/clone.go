@@ -0,0 +1,293 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// clone returns the source for L's Clone, Children, MapChildren, and
+// WithChildren functions. A node's children are those visited by
+// Walk: its non-nil terminals, product values, and non-terminal
+// values, including the elements of slices and optional fields.
+func (L lang) clone() string {
+	var b, clones, maps, sames strings.Builder
+
+	var leaves []string
+	for _, n := range L.nodes() {
+		if n.term || len(n.fields) == 0 && L.meta == nil {
+			leaves = append(leaves, n.name)
+			continue
+		}
+
+		var cl strings.Builder
+		for _, field := range n.fields {
+			x := "n." + field.Name()
+			if c := L.cloneExpr(x, field.Type()); c != x {
+				fmt.Fprintf(&cl, "%v = %v\n", x, c)
+			}
+		}
+		if cl.Len() != 0 {
+			fmt.Fprintf(&clones, "case %v:\n%vreturn n\n", n.name, cl.String())
+		}
+
+		var mp strings.Builder
+		for _, field := range n.fields {
+			x := "n." + field.Name()
+			if m := L.mapCall(x, field.Type()); m != "" {
+				fmt.Fprintf(&mp, "if x, ok := %v; ok {\n%v, changed = x, true\n}\n", m, x)
+			}
+		}
+		if mp.Len() != 0 {
+			fmt.Fprintf(&maps, "case %v:\nchanged := false\n%vif changed {\nreturn n\n}\n", n.name, mp.String())
+		}
+
+		var conds []string
+		for _, field := range n.fields {
+			conds = append(conds, L.sameExpr("a."+field.Name(), "b."+field.Name(), field.Type()))
+		}
+		if L.meta != nil {
+			conds = append(conds, fmt.Sprintf("a.%[1]v.Same(b.%[1]v)", L.meta.name))
+		}
+		fmt.Fprintf(&sames, "case %v:\nb, ok := b.(%v)\nreturn ok && %v\n", n.name, n.name, strings.Join(conds, " &&\n"))
+	}
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	fmt.Fprintf(&b, "import (\n\"fmt\"\n\"slices\"\n)\n\n")
+
+	fmt.Fprintf(&b, `// Clone returns a deep copy of x, which shares no slices or pointers
+// with it.
+func Clone[T Node](x T) T {
+	res, _ := clone(x).(T)
+	return res
+}
+
+func clone(x Node) Node {
+	switch n := x.(type) {
+%v}
+	return x
+}
+
+func cloneNode[T Node](x T) T {
+	if Node(x) == nil {
+		return x
+	}
+	return clone(x).(T)
+}
+
+func cloneSlice[T any](xs []T, clone func(T) T) []T {
+	if xs == nil {
+		return nil
+	}
+	res := make([]T, len(xs))
+	for i, x := range xs {
+		res[i] = clone(x)
+	}
+	return res
+}
+
+func clonePtr[T any](p *T, clone func(T) T) *T {
+	if p == nil {
+		return nil
+	}
+	x := clone(*p)
+	return &x
+}
+
+// Children returns the children of x, in the order that Walk visits
+// them.
+func Children(x Node) []Node {
+	var res []Node
+	MapChildren(x, func(child Node) Node {
+		res = append(res, child)
+		return child
+	})
+	return res
+}
+
+// MapChildren returns a copy of x in which each child is replaced by
+// f(child), visiting them in the order of Children. The copy shares
+// any fields and slices whose children f returns unchanged, and if f
+// returns every child unchanged, MapChildren returns x itself. It
+// panics if f returns a value that can't be stored in place of the
+// child.
+func MapChildren(x Node, f func(Node) Node) Node {
+	switch n := x.(type) {
+%v}
+	return x
+}
+
+// WithChildren returns a copy of x in which the children are replaced
+// by children, in the order of Children, sharing as MapChildren does.
+// It panics if there are too many or too few of them.
+func WithChildren(x Node, children []Node) Node {
+	i := 0
+	res := MapChildren(x, func(Node) Node {
+		if i == len(children) {
+			panic(fmt.Sprintf("WithChildren: too few children for %%T", x))
+		}
+		i++
+		return children[i-1]
+	})
+	if i != len(children) {
+		panic(fmt.Sprintf("WithChildren: %%d children for %%T, which has %%d", len(children), x, i))
+	}
+	return res
+}
+
+// mapNode returns a function that returns f(x), as a T, and whether
+// it differs from x.
+func mapNode[T Node](f func(Node) Node) func(T) (T, bool) {
+	return func(x T) (T, bool) {
+		if Node(x) == nil {
+			return x, false
+		}
+		y := f(x)
+		if same(x, y) {
+			return x, false
+		}
+		if y == nil {
+			var zero T
+			return zero, true
+		}
+		res, ok := y.(T)
+		if !ok {
+			panic(fmt.Sprintf("MapChildren: cannot replace %%T with %%T", x, y))
+		}
+		return res, true
+	}
+}
+
+func mapSlice[T any](xs []T, m func(T) (T, bool)) ([]T, bool) {
+	var res []T
+	for i, x := range xs {
+		if y, ok := m(x); ok {
+			if res == nil {
+				res = slices.Clone(xs)
+			}
+			res[i] = y
+		}
+	}
+	if res == nil {
+		return xs, false
+	}
+	return res, true
+}
+
+func mapPtr[T any](p *T, m func(T) (T, bool)) (*T, bool) {
+	if p == nil {
+		return nil, false
+	}
+	x, ok := m(*p)
+	if !ok {
+		return p, false
+	}
+	return &x, true
+}
+
+// same reports whether a and b are the same node: equal terminals, or
+// values of the same production or product type whose fields hold the
+// same nodes and slices, and whose metadata, if any, is the same.
+func same(a, b Node) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+`, clones.String(), maps.String())
+	if len(leaves) != 0 {
+		fmt.Fprintf(&b, "case %v:\nreturn a == b\n", strings.Join(leaves, ", "))
+	}
+	b.WriteString(sames.String())
+	fmt.Fprintf(&b, `}
+	panic("unreachable")
+}
+
+func sameSlice[T any](a, b []T) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+`)
+
+	return b.String()
+}
+
+// cloneExpr returns an expression for a deep copy of x, an expression
+// of type typ.
+func (L lang) cloneExpr(x string, typ types.Type) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		if _, ok := L.defs[typ.Obj().Name()].(*nonterm); ok {
+			return fmt.Sprintf("cloneNode(%v)", x)
+		}
+	case *types.Slice:
+		if L.comparable(typ.Elem()) {
+			return fmt.Sprintf("slices.Clone(%v)", x)
+		}
+		return fmt.Sprintf("cloneSlice(%v, %v)", x, L.cloner(typ.Elem()))
+	case *types.Pointer:
+		return fmt.Sprintf("clonePtr(%v, %v)", x, L.cloner(typ.Elem()))
+	}
+	return x
+}
+
+// cloner returns a function that makes deep copies of values of type
+// typ.
+func (L lang) cloner(typ types.Type) string {
+	if tparam, ok := typ.(*types.TypeParam); ok {
+		if _, ok := L.defs[tparam.Obj().Name()].(*nonterm); ok {
+			return fmt.Sprintf("cloneNode[%v]", tparam.Obj().Name())
+		}
+	}
+	t := types.TypeString(typ, nil)
+	return fmt.Sprintf("func(x %v) %v { return %v }", t, t, L.cloneExpr("x", typ))
+}
+
+// mapCall returns an expression for the result of applying f to the
+// children within x, an expression of type typ, and whether any
+// changed. It returns "" if x can't contain children.
+func (L lang) mapCall(x string, typ types.Type) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		return fmt.Sprintf("mapNode[%v](f)(%v)", typ.Obj().Name(), x)
+	case *types.Slice:
+		if m := L.mapper(typ.Elem()); m != "" {
+			return fmt.Sprintf("mapSlice(%v, %v)", x, m)
+		}
+	case *types.Pointer:
+		if m := L.mapper(typ.Elem()); m != "" {
+			return fmt.Sprintf("mapPtr(%v, %v)", x, m)
+		}
+	}
+	return ""
+}
+
+// mapper returns a function that applies f to the children within a
+// value of type typ, or "" if it can't contain children.
+func (L lang) mapper(typ types.Type) string {
+	if tparam, ok := typ.(*types.TypeParam); ok {
+		return fmt.Sprintf("mapNode[%v](f)", tparam.Obj().Name())
+	}
+	m := L.mapCall("x", typ)
+	if m == "" {
+		return ""
+	}
+	t := types.TypeString(typ, nil)
+	return fmt.Sprintf("func(x %v) (%v, bool) { return %v }", t, t, m)
+}
+
+// sameExpr returns an expression that reports whether x and y,
+// expressions of type typ, hold the same nodes and slices.
+func (L lang) sameExpr(x, y string, typ types.Type) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		if _, ok := L.defs[typ.Obj().Name()].(*nonterm); ok {
+			return fmt.Sprintf("same(%v, %v)", x, y)
+		}
+	case *types.Slice:
+		return fmt.Sprintf("sameSlice(%v, %v)", x, y)
+	}
+	return fmt.Sprintf("%v == %v", x, y)
+}
@@ -0,0 +1,298 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"html"
+	"io"
+	"strings"
+)
+
+// A report describes the changes from one language to another.
+type report struct {
+	from, to string // from is empty for a language's full contents
+	entry    item   // empty if the entry is unchanged
+	meta     item   // empty if the metadata field is unchanged
+	sections []section
+}
+
+// A section lists the changes to a single definition.
+type section struct {
+	name  string
+	items []item
+}
+
+// An item describes a single change. Its odd-indexed parts are code.
+type item []string
+
+// delta returns a report of the changes from L0 to L. L0 need not
+// immediately precede L.
+func delta(L0, L lang) report {
+	r := report{from: L0.name, to: L.name}
+
+	if L0.entry != L.entry {
+		switch {
+		case L0.entry == "":
+			r.entry = item{"entry ", L.entry}
+		case L.entry == "":
+			r.entry = item{"removed entry ", L0.entry}
+		default:
+			r.entry = item{"changed entry from ", L0.entry, " to ", L.entry}
+		}
+	}
+
+	if m0, m := L0.meta.String(), L.meta.String(); m0 != m {
+		switch {
+		case m0 == "":
+			r.meta = item{"metadata field ", m}
+		case m == "":
+			r.meta = item{"removed metadata field ", m0}
+		default:
+			r.meta = item{"changed metadata field from ", m0, " to ", m}
+		}
+	}
+
+	names := make(map[string]bool)
+	for defName := range L0.defs {
+		names[defName] = true
+	}
+	for defName := range L.defs {
+		names[defName] = true
+	}
+
+	for _, defName := range keys(names) {
+		def0, def := L0.defs[defName], L.defs[defName]
+
+		var items []item
+		switch {
+		case def0 == nil:
+			items = added(defName, def)
+		case def == nil:
+			items = []item{{"removed " + kind(def0)}}
+		case kind(def0) != kind(def):
+			items = []item{{fmt.Sprintf("changed from %v to %v", kind(def0), kind(def))}}
+			items = append(items, added(defName, def)[1:]...)
+		default:
+			items = changed(def0, def)
+		}
+
+		if len(items) != 0 {
+			r.sections = append(r.sections, section{defName, items})
+		}
+	}
+
+	return r
+}
+
+// kind returns a description of what sort of definition def is.
+func kind(def Define) string {
+	switch def := def.(type) {
+	case *term:
+		return "terminal"
+	case *nonterm:
+		if def.str != nil {
+			return "product"
+		}
+	}
+	return "non-terminal"
+}
+
+// added returns the items describing the addition of def. The first
+// item describes def itself, and the rest describe its contents.
+func added(defName string, def Define) []item {
+	res := []item{{"added " + kind(def)}}
+	if t, ok := def.(*term); ok {
+		if t.repr != nil {
+			res = append(res, item{"represented by ", reprString(t.repr)})
+		}
+		for _, p := range t.sortedPrims() {
+			res = append(res, item{"primitive ", primSig(p)})
+		}
+	}
+	if nt, ok := def.(*nonterm); ok {
+		if nt.str != nil {
+			return append(res, item{"fields ", fieldsSig(structFields(nt.str))})
+		}
+		for _, embed := range keys(nt.embeds) {
+			res = append(res, item{"embeds ", "*" + embed})
+		}
+		for _, conName := range keys(nt.cons) {
+			res = append(res, item{"production ", conSig(nt.cons[conName])})
+		}
+	}
+	return res
+}
+
+// changed returns the items describing the changes from def0 to def,
+// which are the same kind of definition.
+func changed(def0, def Define) []item {
+	var res []item
+	switch def0 := def0.(type) {
+	case *term:
+		def := def.(*term)
+		if def0.pass != def.pass {
+			res = append(res, item{"redefined in " + def.pass})
+		}
+		if old, new := reprString(def0.repr), reprString(def.repr); old != new {
+			res = append(res, item{"changed representation from ", old, " to ", new})
+		}
+		for _, p0 := range def0.sortedPrims() {
+			if def.prims[p0.fn.Name()] == nil {
+				res = append(res, item{"removed primitive ", primSig(p0)})
+			}
+		}
+		for _, p := range def.sortedPrims() {
+			p0 := def0.prims[p.fn.Name()]
+			switch {
+			case p0 == nil:
+				res = append(res, item{"added primitive ", primSig(p)})
+			case primSig(p0) != primSig(p):
+				res = append(res, item{"changed primitive ", primSig(p0), " to ", primSig(p)})
+			}
+		}
+	case *nonterm:
+		def := def.(*nonterm)
+		if def0.str != nil {
+			if old, new := fieldsSig(structFields(def0.str)), fieldsSig(structFields(def.str)); old != new {
+				res = append(res, item{"changed fields from ", old, " to ", new})
+			}
+			break
+		}
+
+		for _, embed := range keys(def0.embeds) {
+			if !def.embeds[embed] {
+				res = append(res, item{"removed embedding ", "*" + embed})
+			}
+		}
+		for _, embed := range keys(def.embeds) {
+			if !def0.embeds[embed] {
+				res = append(res, item{"added embedding ", "*" + embed})
+			}
+		}
+
+		for _, conName := range keys(def0.cons) {
+			if def.cons[conName] == nil {
+				res = append(res, item{"removed production ", conSig(def0.cons[conName])})
+			}
+		}
+		for _, conName := range keys(def.cons) {
+			con0, con := def0.cons[conName], def.cons[conName]
+			switch {
+			case con0 == nil:
+				res = append(res, item{"added production ", conSig(con)})
+			case conSig(con0) != conSig(con):
+				res = append(res, item{"changed production ", conSig(con0), " to ", conSig(con)})
+			}
+		}
+	}
+	return res
+}
+
+// conSig returns the signature of the production declared by con,
+// as written in the language declarations.
+func conSig(con *types.Func) string {
+	return con.Name() + "(" + fieldsSig(tupleVars(con.Type().(*types.Signature).Params())) + ")"
+}
+
+// fieldsSig returns a comma-separated list of fields and their types.
+func fieldsSig(fields []*types.Var) string {
+	var parts []string
+	for _, field := range fields {
+		parts = append(parts, field.Name()+" "+fieldType(field))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reprString returns the Go representation of a terminal, given its
+// repr.
+func reprString(repr types.Type) string {
+	if repr == nil {
+		return "int"
+	}
+	return types.TypeString(repr, (*types.Package).Name)
+}
+
+// title returns a heading for r.
+func (r report) title() string {
+	if r.from == "" {
+		return r.to
+	}
+	return r.from + " → " + r.to
+}
+
+// writeMarkdown writes reports to w as Markdown.
+func writeMarkdown(w io.Writer, reports []report) {
+	for i, r := range reports {
+		if i > 0 {
+			fmt.Fprintf(w, "\n")
+		}
+		fmt.Fprintf(w, "# %v\n", r.title())
+		if r.entry != nil {
+			fmt.Fprintf(w, "\n%v\n", r.entry.markdown())
+		}
+		if r.meta != nil {
+			fmt.Fprintf(w, "\n%v\n", r.meta.markdown())
+		}
+		if len(r.sections) == 0 {
+			fmt.Fprintf(w, "\nNo changes.\n")
+		}
+		for _, s := range r.sections {
+			fmt.Fprintf(w, "\n## %v\n\n", s.name)
+			for _, it := range s.items {
+				fmt.Fprintf(w, "- %v\n", it.markdown())
+			}
+		}
+	}
+}
+
+// writeHTML writes reports to w as a standalone HTML document.
+func writeHTML(w io.Writer, reports []report) {
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Languages</title>\n</head>\n<body>\n")
+	for _, r := range reports {
+		fmt.Fprintf(w, "<h1>%v</h1>\n", html.EscapeString(r.title()))
+		if r.entry != nil {
+			fmt.Fprintf(w, "<p>%v</p>\n", r.entry.html())
+		}
+		if r.meta != nil {
+			fmt.Fprintf(w, "<p>%v</p>\n", r.meta.html())
+		}
+		if len(r.sections) == 0 {
+			fmt.Fprintf(w, "<p>No changes.</p>\n")
+		}
+		for _, s := range r.sections {
+			fmt.Fprintf(w, "<h2>%v</h2>\n<ul>\n", html.EscapeString(s.name))
+			for _, it := range s.items {
+				fmt.Fprintf(w, "<li>%v</li>\n", it.html())
+			}
+			fmt.Fprintf(w, "</ul>\n")
+		}
+	}
+	fmt.Fprintf(w, "</body>\n</html>\n")
+}
+
+func (it item) markdown() string {
+	var b strings.Builder
+	for i, part := range it {
+		if i%2 == 1 {
+			part = "`" + part + "`"
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+func (it item) html() string {
+	var b strings.Builder
+	for i, part := range it {
+		part = html.EscapeString(part)
+		if i%2 == 1 {
+			part = "<code>" + part + "</code>"
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const deltaSrc = `package lang
+
+type L0[
+	Prim interface {
+		define
+		Add(x, y any) (value, pure)
+	},
+	Symbol interface{ define; string },
+	Expr interface {
+		entry
+		*Symbol
+		*Prim
+		Apply(Fun Expr, Args []Expr)
+		If(Cond, Then, Else Expr)
+		Not(X Expr)
+	},
+] language
+
+type L1[
+	Prim interface {
+		redefine
+		Sub(x, y any) (value, pure)
+	},
+	Expr interface {
+		*Prim | omit
+		Not() omit
+		If(Cond Pred, Then, Else Expr)
+		PrimCall(Prim Prim, Args []Expr)
+	},
+	Pred interface {
+		True()
+		Less(X, Y Expr)
+	},
+	Meta meta,
+] language
+`
+
+func TestDelta(t *testing.T) {
+	chain, _ := build(load(t, deltaSrc), "out")
+	if got := reported(); len(got) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", got)
+	}
+
+	var b strings.Builder
+	writeMarkdown(&b, []report{delta(lang{}, chain[0]), delta(chain[0], chain[1]), delta(chain[1], chain[1])})
+	// Code is quoted with ' in place of `, which a raw string can't hold.
+	want := strings.ReplaceAll(`# L0
+
+entry 'Expr'
+
+## Expr
+
+- added non-terminal
+- embeds '*Prim'
+- embeds '*Symbol'
+- production 'Apply(Fun Expr, Args []Expr)'
+- production 'If(Cond Expr, Then Expr, Else Expr)'
+- production 'Not(X Expr)'
+
+## Prim
+
+- added terminal
+- primitive 'Add(x any, y any) (value, pure)'
+
+## Symbol
+
+- added terminal
+- represented by 'string'
+
+# L0 → L1
+
+metadata field 'Meta lang.Meta[any]'
+
+## Expr
+
+- removed embedding '*Prim'
+- removed production 'Not(X Expr)'
+- changed production 'If(Cond Expr, Then Expr, Else Expr)' to 'If(Cond Pred, Then Expr, Else Expr)'
+- added production 'PrimCall(Prim Prim, Args []Expr)'
+
+## Pred
+
+- added non-terminal
+- production 'Less(X Expr, Y Expr)'
+- production 'True()'
+
+## Prim
+
+- redefined in L1
+- added primitive 'Sub(x any, y any) (value, pure)'
+
+# L1 → L1
+
+No changes.
+`, "'", "`")
+	if got := b.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDeltaHTML(t *testing.T) {
+	chain, _ := build(load(t, deltaSrc), "out")
+
+	var b strings.Builder
+	writeHTML(&b, []report{delta(chain[0], chain[1])})
+	got := b.String()
+	for _, want := range []string{
+		"<h1>L0 → L1</h1>\n",
+		"<h2>Expr</h2>\n<ul>\n<li>removed embedding <code>*Prim</code></li>\n",
+		"<li>changed production <code>If(Cond Expr, Then Expr, Else Expr)</code> to <code>If(Cond Pred, Then Expr, Else Expr)</code></li>\n",
+		"<li>added primitive <code>Sub(x any, y any) (value, pure)</code></li>\n",
+		"<p>metadata field <code>Meta lang.Meta[any]</code></p>\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML report is missing %q:\n%s", want, got)
+		}
+	}
+}
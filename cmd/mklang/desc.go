@@ -0,0 +1,139 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// langPath is the import path of the language descriptor runtime
+// package used by generated code.
+const langPath = "github.com/mdempsky/hermes/lang"
+
+// desc returns the source for L's Language descriptor, which is
+// registered with the lang package when the generated package is
+// initialized.
+func (L lang) desc() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	fmt.Fprintf(&b, "import (\n\"reflect\"\n\n%q\n)\n\n", langPath)
+
+	fmt.Fprintf(&b, "// Language describes %v.\n", L.name)
+	fmt.Fprintf(&b, "var Language = &lang.Language{\nName: %q,\nPath: %q,\n", L.name, L.path)
+	if L.entry != "" {
+		fmt.Fprintf(&b, "Entry: %q,\n", L.entry)
+	}
+	if L.vars != "" {
+		fmt.Fprintf(&b, "Var: %q,\n", L.vars)
+	}
+	if L.meta != nil {
+		fmt.Fprintf(&b, "Meta: %q,\n", L.meta.name)
+	}
+	fmt.Fprintf(&b, "Defs: []*lang.Def{\n")
+	for _, defName := range keys(L.defs) {
+		fmt.Fprintf(&b, "{\nName: %q,\n", defName)
+		switch def := L.defs[defName].(type) {
+		case *term:
+			fmt.Fprintf(&b, "Kind: lang.Terminal,\nFrom: %q,\n", def.pass)
+			fmt.Fprintf(&b, "GoType: reflect.TypeFor[%v](),\n", defName)
+			descNames(&b, "IsAlso", def.isAlso, defName)
+			if prims := def.sortedPrims(); len(prims) != 0 {
+				fmt.Fprintf(&b, "Prims: []*lang.Prim{\n")
+				for _, p := range prims {
+					fmt.Fprintf(&b, "%v[%v%v],\n", primTable(defName), defName, p.fn.Name())
+				}
+				fmt.Fprintf(&b, "},\n")
+			}
+		case *nonterm:
+			if def.str != nil {
+				fmt.Fprintf(&b, "Kind: lang.Product,\nFrom: %q,\n", def.pass)
+				fmt.Fprintf(&b, "GoType: reflect.TypeFor[%v](),\n", defName)
+				L.descFields(&b, structFields(def.str))
+				break
+			}
+			fmt.Fprintf(&b, "Kind: lang.NonTerminal,\nFrom: %q,\n", def.pass)
+			fmt.Fprintf(&b, "GoType: reflect.TypeFor[%v](),\n", defName)
+			descNames(&b, "IsAlso", def.isAlso, defName)
+			descNames(&b, "Embeds", def.embeds, "")
+			if len(def.cons) != 0 {
+				fmt.Fprintf(&b, "Cons: []*lang.Con{\n")
+				for _, conName := range keys(def.cons) {
+					fmt.Fprintf(&b, "{\nName: %q,\nFrom: %q,\n", conName, def.from[conName])
+					fmt.Fprintf(&b, "GoType: reflect.TypeFor[%v](),\n", conName)
+					L.descFields(&b, tupleVars(def.cons[conName].Type().(*types.Signature).Params()))
+					fmt.Fprintf(&b, "},\n")
+				}
+				fmt.Fprintf(&b, "},\n")
+			}
+		}
+		fmt.Fprintf(&b, "},\n")
+	}
+	fmt.Fprintf(&b, "},\n}\n\n")
+
+	fmt.Fprintf(&b, "func init() { lang.Register(Language) }\n")
+
+	return b.String()
+}
+
+// descNames writes a field named key that lists the sorted names in
+// set, except for skip, if there are any.
+func descNames(b *strings.Builder, key string, set map[string]bool, skip string) {
+	var names []string
+	for _, name := range keys(set) {
+		if name != skip {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(names) != 0 {
+		fmt.Fprintf(b, "%v: []string{%v},\n", key, strings.Join(names, ", "))
+	}
+}
+
+// descFields writes the Fields of a descriptor, if there are any.
+func (L lang) descFields(b *strings.Builder, fields []*types.Var) {
+	if len(fields) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "Fields: []lang.Field{\n")
+	for _, field := range fields {
+		fmt.Fprintf(b, "{Name: %q, Type: %v", field.Name(), L.descType(field.Type()))
+		switch roles[field] {
+		case binds:
+			fmt.Fprintf(b, ", Bind: true")
+		case scoped:
+			fmt.Fprintf(b, ", Scope: true")
+		}
+		fmt.Fprintf(b, "},\n")
+	}
+	fmt.Fprintf(b, "},\n")
+}
+
+// descType returns an expression that describes typ, the type of a
+// field.
+func (L lang) descType(typ types.Type) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		defName := typ.Obj().Name()
+		kind := "Terminal"
+		if nt, ok := L.defs[defName].(*nonterm); ok {
+			kind = "NonTerminal"
+			if nt.str != nil {
+				kind = "Product"
+			}
+		}
+		return fmt.Sprintf("&lang.Type{Kind: lang.%v, Name: %q}", kind, defName)
+	case *types.Slice:
+		return fmt.Sprintf("&lang.Type{Kind: lang.Slice, Elem: %v}", L.descType(typ.Elem()))
+	case *types.Pointer:
+		return fmt.Sprintf("&lang.Type{Kind: lang.Optional, Elem: %v}", L.descType(typ.Elem()))
+	case *types.Basic:
+		return fmt.Sprintf("&lang.Type{Kind: lang.Int, Name: %q}", typ.Name())
+	}
+	panic(fmt.Sprintf("unexpected field type %v", typ)) // see checkField
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// fset is the file set for the loaded language declarations.
+var fset *token.FileSet
+
+// A diagnostic is an error or warning found while processing the
+// language declarations.
+type diagnostic struct {
+	pos     token.Position
+	msg     string
+	warning bool
+}
+
+var diagnostics []diagnostic
+
+// errorf reports an error at pos, which may be token.NoPos if the
+// error has no corresponding source position.
+func errorf(pos token.Pos, format string, args ...any) {
+	diagnostics = append(diagnostics, diagnostic{
+		pos: fset.Position(pos),
+		msg: fmt.Sprintf(format, args...),
+	})
+}
+
+// warnf reports a warning at pos. Warnings don't prevent generating
+// code, unless -strict is set, in which case they're errors instead.
+func warnf(pos token.Pos, format string, args ...any) {
+	errorf(pos, format, args...)
+	diagnostics[len(diagnostics)-1].warning = !*strictFlag
+}
+
+// hasErrors reports whether any errors have been reported.
+func hasErrors() bool {
+	for _, d := range diagnostics {
+		if !d.warning {
+			return true
+		}
+	}
+	return false
+}
+
+// sortDiagnostics sorts the reported diagnostics by position.
+func sortDiagnostics() {
+	slices.SortStableFunc(diagnostics, func(a, b diagnostic) int {
+		return cmp.Or(
+			cmp.Compare(a.pos.Filename, b.pos.Filename),
+			cmp.Compare(a.pos.Line, b.pos.Line),
+			cmp.Compare(a.pos.Column, b.pos.Column),
+		)
+	})
+}
+
+// flushDiagnostics prints any reported errors and warnings to
+// standard error, sorted by position, and exits with a non-zero
+// status if there were any errors.
+func flushDiagnostics() {
+	if len(diagnostics) == 0 {
+		return
+	}
+
+	sortDiagnostics()
+	wd, _ := os.Getwd()
+	for _, d := range diagnostics {
+		if rel, err := filepath.Rel(wd, d.pos.Filename); err == nil && d.pos.Filename != "" {
+			d.pos.Filename = rel
+		}
+		if d.warning {
+			d.msg = "warning: " + d.msg
+		}
+		if d.pos.IsValid() {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", d.pos, d.msg)
+		} else {
+			fmt.Fprintf(os.Stderr, "mklang: %v\n", d.msg)
+		}
+	}
+	if hasErrors() {
+		os.Exit(1)
+	}
+	diagnostics = nil
+}
@@ -0,0 +1,463 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "ok",
+			src: `package lang
+
+type L0[
+	Symbol interface{ define; string },
+	Expr interface {
+		entry
+		*Symbol
+		Apply(Fun Expr, Args []Expr)
+	},
+] language
+`,
+		},
+		{
+			name: "already defined",
+			src: `package lang
+
+type L0[
+	Symbol interface{ define; string },
+	Expr interface {
+		entry
+		*Symbol
+	},
+] language
+
+type L1[
+	Symbol interface{ define; string },
+] language
+`,
+			want: []string{"12:2: Symbol is already defined"},
+		},
+		{
+			name: "undefined",
+			src: `package lang
+
+type L0[
+	Expr interface {
+		entry
+		Nop()
+	},
+] language
+
+type L1[
+	Symbol inherit,
+	Stmt interface{ redefine },
+] language
+`,
+			want: []string{
+				"11:2: cannot inherit undefined Symbol",
+				"12:2: cannot redefine undefined Stmt",
+			},
+		},
+		{
+			name: "unknown command",
+			src: `package lang
+
+type L0[
+	Expr interface {
+		entry
+		Nop()
+	},
+	Stmt value,
+] language
+`,
+			want: []string{"8:2: unknown command value for Stmt"},
+		},
+		{
+			name: "set and product",
+			src: `package lang
+
+type L0[
+	Expr interface {
+		entry
+		Let(Bindings []Binding)
+	},
+	Binding struct{ X Expr },
+] language
+
+type L1[
+	Binding interface {
+		Nop()
+	},
+] language
+`,
+			want: []string{"12:2: Binding is both a set and product type"},
+		},
+		{
+			name: "sorted",
+			src: `package lang
+
+type L0[
+	Expr interface {
+		entry
+		Nop()
+	},
+] language
+
+type L1[
+	Stmt inherit,
+] language
+
+type L2[
+	Expr interface {
+		Missing(X Undefined)
+	},
+	Undefined inherit,
+] language
+`,
+			want: []string{
+				"11:2: cannot inherit undefined Stmt",
+				"16:11: field X refers to Undefined, which is not defined in L2",
+				"18:2: cannot inherit undefined Undefined",
+			},
+		},
+		{
+			name: "warning",
+			src: `package lang
+
+type L0[
+	Expr interface {
+		entry
+		Nop()
+	},
+] language
+
+type L1[
+	Stmt omit,
+] language
+`,
+			want: []string{"11:2: warning: redundant omit: Stmt is not defined"},
+		},
+		{
+			name: "undefined entry",
+			src: `package lang
+
+type L0[
+	Program entry,
+	Expr interface {
+		entry
+		Nop()
+	},
+] language
+
+type L1[
+	Expr omit,
+	Stmt interface {
+		Nop()
+	},
+] language
+`,
+			want: []string{
+				"4:2: entry Program is not a defined non-terminal",
+				"11:6: entry Expr is not defined in L1",
+			},
+		},
+		{
+			name: "two entries",
+			src: `package lang
+
+type L0[
+	Expr interface {
+		entry
+		Nop()
+	},
+	Stmt interface {
+		entry
+		Skip()
+	},
+] language
+`,
+			// Only the first entry counts, so Stmt is pruned.
+			want: []string{
+				"8:2: L0 already has entry Expr",
+				"8:2: warning: Stmt is unreachable from entry Expr in L0",
+			},
+		},
+		{
+			name: "pruned",
+			src: `package lang
+
+type L0[
+	Expr interface {
+		entry
+		Let(Bindings []Binding, Body Expr)
+	},
+	Binding struct{ Val Stmt },
+	Stmt interface {
+		Skip()
+	},
+] language
+
+type L1[
+	Expr interface {
+		Let() omit
+		Nop()
+	},
+	Decl interface {
+		Var(Val Expr)
+	},
+	Stmt omit,
+] language
+`,
+			// Binding and Stmt are inherited, so they're pruned
+			// silently, but Decl is new to L1.
+			want: []string{
+				"19:2: warning: Decl is unreachable from entry Expr in L1",
+				"22:2: warning: redundant omit: Stmt is unreachable from entry Expr",
+			},
+		},
+		{
+			name: "dangling product reference",
+			src: `package lang
+
+type L0[
+	Expr interface {
+		entry
+		Let(Bindings []Binding, Body Expr)
+	},
+	Binding struct{ Val Stmt },
+	Stmt interface {
+		Skip()
+	},
+] language
+
+type L1[
+	Stmt omit,
+] language
+`,
+			want: []string{"8:18: field Val refers to Stmt, which is not defined in L1"},
+		},
+		{
+			name: "bound non-terminal",
+			src: `package lang
+
+type L0[
+	Symbol interface{ define; string },
+	Expr interface {
+		entry
+		*Symbol
+		Closures(X bind[Symbol], Body bind[LabelsBody])
+	},
+	LabelsBody interface {
+		Labels(L bind[[]Symbol], Body scope[Expr])
+	},
+] language
+`,
+		},
+		{
+			name: "misused bound non-terminal",
+			src: `package lang
+
+type L0[
+	Symbol interface{ define; string },
+	Expr interface {
+		entry
+		*Symbol
+		Closures(Body bind[LabelsBody])
+		Let(Body LabelsBody)
+		Loop(Body bind[Expr])
+	},
+	LabelsBody interface {
+		Labels(L bind[Symbol], Body scope[Expr])
+	},
+] language
+`,
+			want: []string{
+				"9:7: field Body refers to LabelsBody, which is bound by other fields; it can only be used in binding fields",
+				"10:8: field Body binds Expr, which embeds Symbol; a bound non-terminal can only have its own productions",
+				"13:26: field Body refers to Expr, which is bound by other fields; it can only be used in binding fields",
+			},
+		},
+		{
+			name: "variables",
+			src: `package lang
+
+type L0[
+	Symbol interface{ define; string },
+	Label interface{ define; string },
+	Expr interface {
+		entry
+		*Symbol
+		Lambda(Params bind[[]Symbol], Body scope[Expr])
+		Labels(L bind[Label], Body scope[Expr])
+	},
+] language
+`,
+			want: []string{"9:10: field Params binds Symbol, but field L of L0 binds Label; a language can only have one kind of variable"},
+		},
+		{
+			name: "representations",
+			src: `package lang
+
+type Name string
+
+type Reg uint8
+
+type Op int
+
+func (o Op) String() string { return "op" }
+func (o *Op) UnmarshalText(b []byte) error { return nil }
+
+type L0[
+	Symbol interface{ define; Name },
+	Register interface{ define; Reg },
+	Oper interface{ define; Op },
+	Expr interface {
+		entry
+		*Symbol
+		Load(R Register, O Oper)
+	},
+] language
+`,
+		},
+		{
+			name: "String without UnmarshalText",
+			src: `package lang
+
+type Op int
+
+func (o Op) String() string { return "op" }
+
+type L0[
+	Oper interface{ define; Op },
+	Expr interface {
+		entry
+		*Oper
+	},
+] language
+`,
+			want: []string{"8:2: Oper is represented by lang.Op, which has a String method but no UnmarshalText method"},
+		},
+		{
+			name: "unsupported representation",
+			src: `package lang
+
+type L0[
+	Real interface{ define; float64 },
+	Expr interface {
+		entry
+		*Real
+	},
+] language
+`,
+			want: []string{"4:2: Real has unsupported representation float64; want an integer or string type"},
+		},
+		{
+			name: "two representations",
+			src: `package lang
+
+type L0[
+	Symbol interface{ define; string; int },
+	Expr interface {
+		entry
+		*Symbol
+	},
+] language
+`,
+			want: []string{"4:2: Symbol has more than one representation: string and int"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, files := build(load(t, tt.src), "out")
+			if got := reported(); !slices.Equal(got, tt.want) {
+				t.Errorf("got diagnostics:\n\t%v\nwant:\n\t%v", strings.Join(got, "\n\t"), strings.Join(tt.want, "\n\t"))
+			}
+			if len(tt.want) == 0 && len(files) == 0 {
+				t.Errorf("generated no files")
+			}
+		})
+	}
+}
+
+func TestStrict(t *testing.T) {
+	defer func(strict bool) { *strictFlag = strict }(*strictFlag)
+	*strictFlag = true
+
+	pkg := load(t, `package lang
+
+type L0[
+	Expr interface {
+		entry
+		Nop()
+	},
+] language
+
+type L1[
+	Stmt omit,
+] language
+`)
+	build(pkg, "out")
+	want := []string{"11:2: redundant omit: Stmt is not defined"}
+	if got := reported(); !slices.Equal(got, want) {
+		t.Errorf("got diagnostics %q, want %q", got, want)
+	}
+	if !hasErrors() {
+		t.Errorf("redundant omit is not an error with -strict")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	chain, _ := build(load(t, `package lang
+
+type L0[
+	Expr interface {
+		entry
+		Let(Bindings []Binding, Body Expr)
+	},
+	Binding struct{ Val Stmt },
+	Stmt interface {
+		Skip()
+	},
+	Symbol interface {
+		define
+		string
+	},
+] language
+
+type L1[
+	Expr interface {
+		Let() omit
+		Nop()
+	},
+] language
+`), "out")
+	if got := reported(); len(got) != 1 || !strings.Contains(got[0], "Symbol is unreachable") {
+		t.Errorf("got diagnostics %q, want Symbol unreachable in L0", got)
+	}
+
+	// Symbol is pruned from L0, and Binding and Stmt from L1, once
+	// Let is gone.
+	tests := []struct {
+		L    lang
+		want []string
+	}{
+		{chain[0], []string{"Binding", "Expr", "Stmt"}},
+		{chain[1], []string{"Expr"}},
+	}
+	for _, tt := range tests {
+		if got := keys(tt.L.defs); !slices.Equal(got, tt.want) {
+			t.Errorf("%v defines %v, want %v", tt.L.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// An edit is a single line of a line-based diff.
+type edit struct {
+	op   byte // ' ', '-', or '+'
+	line string
+}
+
+// diffLines returns a minimal edit script that transforms x into y,
+// computed from their longest common subsequence of lines.
+func diffLines(x, y []string) []edit {
+	// lcs[i][j] is the length of the longest common subsequence of
+	// x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var res []edit
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			res = append(res, edit{' ', x[i]})
+			i++
+			j++
+		case j == len(y) || i < len(x) && lcs[i+1][j] >= lcs[i][j+1]:
+			res = append(res, edit{'-', x[i]})
+			i++
+		default:
+			res = append(res, edit{'+', y[j]})
+			j++
+		}
+	}
+	return res
+}
+
+// unified returns a unified diff, with three lines of context, that
+// transforms old into new. It returns the empty string if they're
+// equal.
+func unified(oldName, newName, old, new string) string {
+	const context = 3
+
+	edits := diffLines(splitLines(old), splitLines(new))
+
+	var b strings.Builder
+	for start := 0; start < len(edits); {
+		// Find the next change.
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+
+		// Extend the hunk until there's a long enough run of
+		// unchanged lines.
+		end := start
+		for same := 0; end < len(edits) && same <= 2*context; end++ {
+			if edits[end].op == ' ' {
+				same++
+			} else {
+				same = 0
+			}
+		}
+		for end > start && edits[end-1].op == ' ' {
+			end--
+		}
+
+		lo := max(start-context, 0)
+		hi := min(end+context, len(edits))
+
+		// Compute the starting line numbers of the hunk.
+		oldLine, newLine := 1, 1
+		for _, e := range edits[:lo] {
+			if e.op != '+' {
+				oldLine++
+			}
+			if e.op != '-' {
+				newLine++
+			}
+		}
+		oldLen, newLen := 0, 0
+		for _, e := range edits[lo:hi] {
+			if e.op != '+' {
+				oldLen++
+			}
+			if e.op != '-' {
+				newLen++
+			}
+		}
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %v\n+++ %v\n", oldName, newName)
+		}
+		fmt.Fprintf(&b, "@@ -%v +%v @@\n", hunkRange(oldLine, oldLen), hunkRange(newLine, newLen))
+		for _, e := range edits[lo:hi] {
+			fmt.Fprintf(&b, "%c%v\n", e.op, e.line)
+		}
+
+		start = hi
+	}
+	return b.String()
+}
+
+func hunkRange(line, n int) string {
+	switch n {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return fmt.Sprint(line)
+	}
+	return fmt.Sprintf("%d,%d", line, n)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dot returns the source for L's WriteDOT function, which wraps the
+// lang package's function of the same name.
+func (L lang) dot() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	fmt.Fprintf(&b, "import (\n\"io\"\n\n%q\n)\n\n", langPath)
+
+	fmt.Fprintf(&b, `// WriteDOT writes x to w as a Graphviz graph, in which productions
+// are nodes, fields are edges, and terminals are listed in the labels
+// of the nodes that hold them. With opts.Prev, such as the tree that a
+// pass translated into x, nodes that changed are highlighted.
+func WriteDOT(w io.Writer, x Node, opts *lang.DOTOptions) error {
+	return lang.WriteDOT(w, Language, x, opts)
+}
+`)
+
+	return b.String()
+}
@@ -0,0 +1,190 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// equal returns the source for L's Equal and Hash functions, which
+// compare and hash syntax trees structurally, since Go's == panics on
+// productions with slice fields.
+func (L lang) equal() string {
+	var b, cases, hashes strings.Builder
+
+	usesSlices, usesEqualSlices := false, false
+	var terms, leaves []string
+	for _, n := range L.nodes() {
+		if n.term {
+			terms = append(terms, n.name)
+			fmt.Fprintf(&hashes, "case %v:\nh.String(%q)\n%v", n.name, n.name, L.hashTerm(n.name, "n"))
+			continue
+		}
+		if len(n.fields) == 0 {
+			fmt.Fprintf(&hashes, "case %v:\nh.String(%q)\n", n.name, n.name)
+			if L.meta != nil {
+				// == would compare the metadata.
+				fmt.Fprintf(&cases, "case %v:\n_, ok := b.(%v)\nreturn ok\n", n.name, n.name)
+				continue
+			}
+			leaves = append(leaves, n.name)
+			continue
+		}
+
+		var conds []string
+		for _, field := range n.fields {
+			x, y := "a."+field.Name(), "b."+field.Name()
+			conds = append(conds, L.equalExpr(x, y, field.Type(), &usesSlices, &usesEqualSlices))
+		}
+		fmt.Fprintf(&cases, "case %v:\nb, ok := b.(%v)\nreturn ok && %v\n", n.name, n.name, strings.Join(conds, " &&\n"))
+
+		fmt.Fprintf(&hashes, "case %v:\nh.String(%q)\n", n.name, n.name)
+		for _, field := range n.fields {
+			L.hashField(&hashes, "n."+field.Name(), field.Type())
+		}
+	}
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	if usesSlices {
+		fmt.Fprintf(&b, "import (\n\"slices\"\n\n%q\n)\n\n", langPath)
+	} else {
+		fmt.Fprintf(&b, "import %q\n\n", langPath)
+	}
+
+	fmt.Fprintf(&b, `// Equal reports whether a and b, which may be values of any
+// non-terminal, are structurally equal: they're the same terminal
+// value, or values of the same production or product type whose
+// fields are equal. Slices are compared element-wise, optional fields
+// by content, and metadata not at all.
+func Equal(a, b Node) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+`)
+	if len(terms)+len(leaves) != 0 {
+		fmt.Fprintf(&b, "case %v:\nreturn a == b\n", strings.Join(append(terms, leaves...), ", "))
+	}
+	b.WriteString(cases.String())
+	fmt.Fprintf(&b, `}
+	panic("unreachable")
+}
+
+// Hash returns a hash of x, such that Equal values have equal hashes,
+// regardless of their metadata.
+// Hashes are the same in every run, so they can be stored.
+func Hash(x Node) uint64 {
+	h := lang.NewHasher()
+	hash(h, x)
+	return h.Sum64()
+}
+
+func hash(h *lang.Hasher, x Node) {
+	switch n := x.(type) {
+	case nil:
+		h.String("nil")
+`)
+	b.WriteString(hashes.String())
+	fmt.Fprintf(&b, "}\n}\n")
+
+	if usesEqualSlices {
+		fmt.Fprintf(&b, `
+func equalSlices[T any](a, b []T, eq func(a, b T) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !eq(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+`)
+	}
+
+	return b.String()
+}
+
+// equalExpr returns an expression that reports whether x and y,
+// expressions of type typ, are equal. It sets *usesSlices and
+// *usesEqualSlices if the expression uses slices.Equal or the
+// generated equalSlices function.
+func (L lang) equalExpr(x, y string, typ types.Type, usesSlices, usesEqualSlices *bool) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		if _, ok := L.defs[typ.Obj().Name()].(*nonterm); ok {
+			return fmt.Sprintf("Equal(%v, %v)", x, y)
+		}
+	case *types.Slice:
+		if L.comparable(typ.Elem()) {
+			*usesSlices = true
+			return fmt.Sprintf("slices.Equal(%v, %v)", x, y)
+		}
+		*usesEqualSlices = true
+		elem := types.TypeString(typ.Elem(), nil)
+		return fmt.Sprintf("equalSlices(%v, %v, func(x, y %v) bool { return %v })", x, y, elem, L.equalExpr("x", "y", typ.Elem(), usesSlices, usesEqualSlices))
+	case *types.Pointer:
+		elem := L.equalExpr("*"+x, "*"+y, typ.Elem(), usesSlices, usesEqualSlices)
+		return fmt.Sprintf("(%v == nil) == (%v == nil) && (%v == nil || %v)", x, y, x, elem)
+	}
+	return fmt.Sprintf("%v == %v", x, y)
+}
+
+// comparable reports whether values of typ can be compared with ==,
+// with the same result as Equal.
+func (L lang) comparable(typ types.Type) bool {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		_, ok := L.defs[typ.Obj().Name()].(*term)
+		return ok
+	case *types.Basic:
+		return true
+	}
+	return false
+}
+
+// hashTerm returns the statements that add x, a value of the named
+// terminal, to the hash h.
+func (L lang) hashTerm(termName, x string) string {
+	t := L.defs[termName].(*term)
+	if t.repr == nil {
+		return fmt.Sprintf("h.Int(int64(%v))\n", x)
+	}
+	return hashBasic(t.repr.Underlying().(*types.Basic), x)
+}
+
+// hashBasic returns the statement that adds x, a value whose
+// underlying type is basic, to the hash h.
+func hashBasic(basic *types.Basic, x string) string {
+	switch {
+	case basic.Info()&types.IsString != 0:
+		return fmt.Sprintf("h.String(string(%v))\n", x)
+	case basic.Info()&types.IsUnsigned != 0:
+		return fmt.Sprintf("h.Uint(uint64(%v))\n", x)
+	}
+	return fmt.Sprintf("h.Int(int64(%v))\n", x)
+}
+
+// hashField writes the statements that add x, an expression of type
+// typ, to the hash h.
+func (L lang) hashField(b *strings.Builder, x string, typ types.Type) {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		fmt.Fprintf(b, "hash(h, %v)\n", x)
+	case *types.Basic:
+		b.WriteString(hashBasic(typ, x))
+	case *types.Slice:
+		fmt.Fprintf(b, "h.Int(int64(len(%v)))\nfor _, x := range %v {\n", x, x)
+		L.hashField(b, "x", typ.Elem())
+		fmt.Fprintf(b, "}\n")
+	case *types.Pointer:
+		fmt.Fprintf(b, "if %v == nil {\nh.Int(0)\n} else {\nh.Int(1)\n", x)
+		L.hashField(b, "*"+x, typ.Elem())
+		fmt.Fprintf(b, "}\n")
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/mdempsky/hermes/sexpr"
+)
+
+// sexprPath is the import path of the s-expression runtime package
+// used by generated code.
+const sexprPath = "github.com/mdempsky/hermes/sexpr"
+
+// head returns the s-expression head used for the named production
+// or terminal.
+func head(name string) string {
+	return sexpr.Fold(name)
+}
+
+// atoms returns the members of the named non-terminal that may be
+// written as bare atoms where it's expected, in the order that Parse
+// tries them: primitives, then literals, then other terminals. That
+// way a primitive's name or an integer is never read as a symbol.
+func (L lang) atoms(defName string) []node {
+	if nt, ok := L.defs[defName].(*nonterm); !ok || nt.str != nil {
+		return nil
+	}
+	var prims, lits, rest []node
+	for _, n := range L.members(defName) {
+		switch {
+		case n.term && L.defs[n.name].(*term).prims != nil:
+			prims = append(prims, n)
+		case n.term:
+			rest = append(rest, n)
+		case L.literal(n):
+			lits = append(lits, n)
+		}
+	}
+	return append(append(prims, lits...), rest...)
+}
+
+// termAtoms returns the names of the terminals among L.atoms(defName).
+func (L lang) termAtoms(defName string) []string {
+	var res []string
+	for _, n := range L.atoms(defName) {
+		if n.term {
+			res = append(res, n.name)
+		}
+	}
+	return res
+}
+
+// literal reports whether n is a literal: a production whose only
+// field is an integer, like Int, which is written as a bare integer.
+// It's not, if another such production belongs to the same
+// non-terminal, since they couldn't be told apart.
+func (L lang) literal(n node) bool {
+	if !intField(n) {
+		return false
+	}
+	for _, defName := range keys(L.defs) {
+		members := L.members(defName)
+		if !slices.ContainsFunc(members, func(m node) bool { return m.name == n.name }) {
+			continue
+		}
+		for _, m := range members {
+			if m.name != n.name && intField(m) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// intField reports whether n is a production with a single integer
+// field.
+func intField(n node) bool {
+	if n.term || n.product || len(n.fields) != 1 {
+		return false
+	}
+	basic, ok := n.fields[0].Type().(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+// application returns the application production of the named
+// non-terminal, if it has exactly one member shaped like one: a
+// non-terminal field, the function, followed by a slice of them, the
+// arguments. A list whose head isn't the head of a form is read as
+// an application, as in "(f x)".
+func (L lang) application(defName string) (node, bool) {
+	if nt, ok := L.defs[defName].(*nonterm); !ok || nt.str != nil {
+		return node{}, false
+	}
+	var res []node
+	for _, n := range L.members(defName) {
+		if len(n.fields) == 2 && L.isSet(n.fields[0].Type()) {
+			if slice, ok := n.fields[1].Type().(*types.Slice); ok && L.isSet(slice.Elem()) {
+				res = append(res, n)
+			}
+		}
+	}
+	if len(res) != 1 {
+		return node{}, false
+	}
+	return res[0], true
+}
+
+// isSet reports whether typ is a non-terminal other than a product
+// type.
+func (L lang) isSet(typ types.Type) bool {
+	tparam, ok := typ.(*types.TypeParam)
+	if !ok {
+		return false
+	}
+	nt, ok := L.defs[tparam.Obj().Name()].(*nonterm)
+	return ok && nt.str == nil
+}
+
+// implicit reports whether n is written as an application without its
+// head, which it is if it's the application production of each
+// non-terminal that it belongs to.
+func (L lang) implicit(n node) bool {
+	found := false
+	for _, defName := range keys(L.defs) {
+		if !slices.ContainsFunc(L.members(defName), func(m node) bool { return m.name == n.name }) {
+			continue
+		}
+		if app, ok := L.application(defName); !ok || app.name != n.name {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// hasApplications reports whether any of L's productions are written
+// as applications without their heads.
+func (L lang) hasApplications() bool {
+	return slices.ContainsFunc(L.nodes(), L.implicit)
+}
+
+// heads reports productions and terminals of L whose s-expression
+// heads collide.
+func (L lang) heads() {
+	seen := make(map[string]string)
+	for _, n := range L.nodes() {
+		if n.product {
+			// Product types are written as bracketed lists, without a head.
+			continue
+		}
+		h := head(n.name)
+		if prev, ok := seen[h]; ok {
+			errorf(n.pos, "%v and %v both have s-expression head %q in %v", prev, n.name, h, L.name)
+		}
+		seen[h] = n.name
+	}
+}
+
+// format returns the source for L's Unparse and Format functions,
+// which render values in nanopass-style s-expression notation.
+//
+// Each production is written as a list headed by its lowercased
+// name, followed by its fields in order; if the last field is a
+// slice, its elements are spliced into the list. Product types are
+// written the same way, but without a head and in square brackets.
+// A terminal is written as a bare atom where a terminal is expected,
+// or where a non-terminal is expected that Parse would read the atom
+// back as the same terminal; otherwise, it's written as a list headed
+// by its lowercased type name. Literals are written as bare integers,
+// and applications without their heads, unless the function would be
+// mistaken for the head of a form. A nil optional field is written
+// as #f.
+func (L lang) format() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	fmt.Fprintf(&b, "import (\n\"fmt\"\n\n%q\n)\n\n", sexprPath)
+
+	fmt.Fprintf(&b, `// Format returns the s-expression notation for node, broken across
+// lines to fit within 80 columns where possible.
+func Format(node Node) string {
+	return sexpr.Sprint(Unparse(node), 80)
+}
+
+// Unparse returns the s-expression notation for node.
+func Unparse(node Node) sexpr.Expr {
+	switch n := node.(type) {
+	case nil:
+		return atom("<nil>")
+`)
+
+	nodes := L.nodes()
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "case %v:\n", n.name)
+		if n.term {
+			fmt.Fprintf(&b, "return &sexpr.List{Elems: []sexpr.Expr{atom(%q), atom(n)}}\n", head(n.name))
+			continue
+		}
+		if L.literal(n) {
+			fmt.Fprintf(&b, "return atom(n.%v)\n", n.fields[0].Name())
+			continue
+		}
+
+		implicit := L.implicit(n)
+		var elems []string
+		if !n.product && !implicit {
+			elems = append(elems, fmt.Sprintf("atom(%q)", head(n.name)))
+		}
+		var rest string
+		for i, field := range n.fields {
+			x := "n." + field.Name()
+			if slice, ok := field.Type().(*types.Slice); ok && i == len(n.fields)-1 {
+				rest = L.unparseSlice(x, slice)
+				continue
+			}
+			elems = append(elems, L.unparse(x, field.Type()))
+		}
+
+		list := fmt.Sprintf("[]sexpr.Expr{%v}", strings.Join(elems, ", "))
+		if rest != "" {
+			if len(elems) == 0 {
+				list = rest
+			} else {
+				list = fmt.Sprintf("append(%v, %v...)", list, rest)
+			}
+		}
+		switch {
+		case n.product:
+			fmt.Fprintf(&b, "return &sexpr.List{Brack: true, Elems: %v}\n", list)
+		case implicit:
+			fmt.Fprintf(&b, "return unparseApplication(%q, %v)\n", head(n.name), list)
+		default:
+			fmt.Fprintf(&b, "return &sexpr.List{Elems: %v}\n", list)
+		}
+	}
+
+	fmt.Fprintf(&b, `}
+	panic(fmt.Sprintf("unexpected node type %%T", node))
+}
+
+func atom(x any) sexpr.Expr {
+	return &sexpr.Atom{Text: fmt.Sprint(x)}
+}
+
+func unparseAll[T any](xs []T, f func(T) sexpr.Expr) []sexpr.Expr {
+	res := make([]sexpr.Expr, len(xs))
+	for i, x := range xs {
+		res[i] = f(x)
+	}
+	return res
+}
+
+func unparseOpt[T any](x *T, f func(T) sexpr.Expr) sexpr.Expr {
+	if x == nil {
+		return atom("#f")
+	}
+	return f(*x)
+}
+`)
+
+	if L.hasApplications() {
+		fmt.Fprintf(&b, `
+// unparseApplication returns the list of elems, which is written
+// without the head h, unless its first element would be mistaken for
+// the head of a form.
+func unparseApplication(h string, elems []sexpr.Expr) sexpr.Expr {
+	if isHead(elems[0]) {
+		elems = append([]sexpr.Expr{atom(h)}, elems...)
+	}
+	return &sexpr.List{Elems: elems}
+}
+`)
+	}
+
+	for _, defName := range keys(L.defs) {
+		if terms := L.termAtoms(defName); len(terms) != 0 {
+			fmt.Fprintf(&b, "\n// unparse%v is like Unparse, but writes terminals as bare atoms\n// where Parse would read them back the same way.\n", defName)
+			fmt.Fprintf(&b, "func unparse%v(x %v) sexpr.Expr {\n", defName, defName)
+			fmt.Fprintf(&b, "switch x.(type) {\ncase %v:\n", strings.Join(terms, ", "))
+			fmt.Fprintf(&b, "if y, ok := tryParse(atom(x), parse%v); ok && y == x {\nreturn atom(x)\n}\n}\nreturn Unparse(x)\n}\n", defName)
+		}
+	}
+
+	for _, n := range nodes {
+		if !n.term {
+			fmt.Fprintf(&b, "\nfunc (n %v) String() string { return Format(n) }", n.name)
+		}
+	}
+
+	return b.String()
+}
+
+// unparse returns an expression that converts x, an expression of
+// type typ, into a sexpr.Expr.
+func (L lang) unparse(x string, typ types.Type) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		defName := typ.Obj().Name()
+		if _, ok := L.defs[defName].(*nonterm); ok {
+			if len(L.termAtoms(defName)) != 0 {
+				return fmt.Sprintf("unparse%v(%v)", defName, x)
+			}
+			return fmt.Sprintf("Unparse(%v)", x)
+		}
+	case *types.Slice:
+		return fmt.Sprintf("&sexpr.List{Elems: %v}", L.unparseSlice(x, typ))
+	case *types.Pointer:
+		return fmt.Sprintf("unparseOpt(%v, %v)", x, L.unparseFunc(typ.Elem()))
+	}
+	return fmt.Sprintf("atom(%v)", x)
+}
+
+// unparseSlice returns an expression that converts x, an expression
+// of slice type typ, into a []sexpr.Expr.
+func (L lang) unparseSlice(x string, typ *types.Slice) string {
+	return fmt.Sprintf("unparseAll(%v, %v)", x, L.unparseFunc(typ.Elem()))
+}
+
+// unparseFunc returns a function literal that converts values of
+// type typ into a sexpr.Expr.
+func (L lang) unparseFunc(typ types.Type) string {
+	return fmt.Sprintf("func(x %v) sexpr.Expr { return %v }", typ, L.unparse("x", typ))
+}
@@ -0,0 +1,28 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestGolden checks that the packages generated for the example
+// languages are up to date, as "go generate" in example would leave
+// them.
+func TestGolden(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	example, pkg := loadExample(t)
+	_, files := build(pkg, filepath.Join(example, "lang"))
+	if got := reported(); len(got) != 0 {
+		t.Fatalf("unexpected diagnostics:\n%v", got)
+	}
+	if !check(filepath.Join(example, "lang"), files) {
+		t.Errorf("generated packages are out of date; run go generate in %v", example)
+	}
+}
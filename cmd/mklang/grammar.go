@@ -0,0 +1,171 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// grammar returns the source for L's package documentation, which
+// shows L's grammar in EBNF, using the s-expression notation read by
+// its Parse functions. Each production is annotated with the language
+// that introduced it.
+func (L lang) grammar() string {
+	// Group the rules: first the entry, then the other non-terminals,
+	// then product types, and then terminals.
+	type rule struct {
+		name, expr, from string
+		alts             []string // if set, expr is their alternation, wrapped
+	}
+	var groups [][]rule
+	var products, terms []rule
+
+	defNames := keys(L.defs)
+	if L.entry != "" {
+		i := 0
+		for defNames[i] != L.entry {
+			i++
+		}
+		defNames = append(append([]string{L.entry}, defNames[:i]...), defNames[i+1:]...)
+	}
+	for _, defName := range defNames {
+		switch def := L.defs[defName].(type) {
+		case *term:
+			if def.prims != nil {
+				var alts []string
+				for _, p := range def.sortedPrims() {
+					alts = append(alts, fmt.Sprintf("%q", p.text()))
+				}
+				slices.Sort(alts)
+				terms = append(terms, rule{name: defName, from: def.pass, alts: alts})
+				continue
+			}
+			expr := "integer"
+			if def.text() {
+				expr = "atom"
+			}
+			terms = append(terms, rule{name: defName, expr: expr, from: def.pass})
+		case *nonterm:
+			if def.str != nil {
+				products = append(products, rule{name: defName, expr: L.grammarSeq(`"["`, structFields(def.str), `"]"`), from: def.pass})
+				continue
+			}
+			group := []rule{{name: defName, expr: strings.Join(append(keys(def.embeds), keys(def.cons)...), " | ")}}
+			for _, conName := range keys(def.cons) {
+				n := node{name: conName, fields: tupleVars(def.cons[conName].Type().(*types.Signature).Params())}
+				open := fmt.Sprintf(`"(" %q`, head(conName))
+				if L.implicit(n) {
+					open = fmt.Sprintf(`"(" [ %q ]`, head(conName))
+				}
+				expr := L.grammarSeq(open, n.fields, `")"`)
+				if L.literal(n) {
+					expr = L.grammarType(n.fields[0].Type()) + " | " + expr
+				}
+				group = append(group, rule{name: conName, expr: expr, from: def.from[conName]})
+			}
+			groups = append(groups, group)
+		}
+	}
+	groups = append(groups, products, terms)
+
+	width := 0
+	for _, group := range groups {
+		for _, r := range group {
+			width = max(width, len(r.name))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "// Package %v declares the syntax of the %v language.\n//\n", L.pkg, L.name)
+	fmt.Fprintf(&b, "// Its grammar is as follows, in EBNF, using the notation read by its\n")
+	fmt.Fprintf(&b, "// Parse functions. Each production is annotated with the language\n")
+	fmt.Fprintf(&b, "// that introduced it, or that last redefined it.")
+	if L.entry != "" {
+		fmt.Fprintf(&b, " The entry is %v.", L.entry)
+	}
+	fmt.Fprintf(&b, "\n//\n// Where a non-terminal is expected, a terminal may also be written as\n")
+	fmt.Fprintf(&b, "// a list headed by its lowercased name, such as (symbol add) for a\n")
+	fmt.Fprintf(&b, "// symbol that would otherwise be read as a primitive.\n")
+	if L.meta != nil {
+		fmt.Fprintf(&b, "//\n// Its productions and product types also have a metadata field,\n")
+		fmt.Fprintf(&b, "// %v, which the notation omits.\n", L.meta.name)
+	}
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "//\n")
+		for _, r := range group {
+			if r.alts != nil {
+				r.expr = grammarAlts(r.alts, width)
+			}
+			line := fmt.Sprintf("%-*v = %v .", width, r.name, r.expr)
+			if r.from != "" {
+				line += "  // from " + r.from
+			}
+			fmt.Fprintf(&b, "//\t%v\n", line)
+		}
+	}
+	fmt.Fprintf(&b, "package %v\n", L.pkg)
+
+	return b.String()
+}
+
+// grammarAlts returns the alternation of alts, wrapped so that each
+// line fits in about 70 columns, with the continuation lines aligned
+// under the first alternative of a rule whose name is width wide.
+func grammarAlts(alts []string, width int) string {
+	indent := strings.Repeat(" ", width+1)
+	var b strings.Builder
+	col := width + 3
+	for i, alt := range alts {
+		if i > 0 {
+			if col+3+len(alt) > 70 {
+				fmt.Fprintf(&b, "\n//\t%v| ", indent)
+				col = width + 3
+			} else {
+				b.WriteString(" | ")
+				col += 3
+			}
+		}
+		b.WriteString(alt)
+		col += len(alt)
+	}
+	return b.String()
+}
+
+// grammarSeq returns the EBNF for a sequence of fields, between open
+// and close. As in the s-expression notation, a slice in the last
+// field is spliced into the sequence.
+func (L lang) grammarSeq(open string, fields []*types.Var, close string) string {
+	parts := []string{open}
+	for i, field := range fields {
+		if slice, ok := field.Type().(*types.Slice); ok && i == len(fields)-1 {
+			parts = append(parts, "{ "+L.grammarType(slice.Elem())+" }")
+			continue
+		}
+		parts = append(parts, L.grammarType(field.Type()))
+	}
+	return strings.Join(append(parts, close), " ")
+}
+
+// grammarType returns the EBNF for a field of type typ.
+func (L lang) grammarType(typ types.Type) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		return typ.Obj().Name()
+	case *types.Slice:
+		return `"(" { ` + L.grammarType(typ.Elem()) + ` } ")"`
+	case *types.Pointer:
+		return "( " + L.grammarType(typ.Elem()) + ` | "#f" )`
+	case *types.Basic:
+		return "integer"
+	}
+	panic(fmt.Sprintf("unexpected field type %v", typ)) // see checkField
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGrammar(t *testing.T) {
+	chain, _ := build(load(t, deltaSrc), "out")
+	if got := reported(); len(got) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", got)
+	}
+
+	// Compare just the grammar, which is indented within the
+	// package's doc comment.
+	var rules []string
+	for _, line := range strings.Split(chain[1].grammar(), "\n") {
+		if rule, ok := strings.CutPrefix(line, "//\t"); ok {
+			rules = append(rules, rule)
+		} else if line == "//" && len(rules) != 0 {
+			rules = append(rules, "")
+		}
+	}
+	got := strings.Join(rules, "\n")
+	want := `Expr     = Symbol | Apply | If | PrimCall .
+Apply    = "(" [ "apply" ] Expr { Expr } ")" .  // from L0
+If       = "(" "if" Pred Expr Expr ")" .  // from L1
+PrimCall = "(" "primcall" Prim { Expr } ")" .  // from L1
+
+Pred     = Less | True .
+Less     = "(" "less" Expr Expr ")" .  // from L1
+True     = "(" "true" ")" .  // from L1
+
+Prim     = "add" | "sub" .  // from L1
+Symbol   = atom .  // from L0`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	doc := chain[1].grammar()
+	for _, want := range []string{"// Package L1 declares", "The entry is Expr.", "metadata field,\n// Meta,"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("package documentation is missing %q:\n%s", want, doc)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// json returns the source for L's MarshalJSON and UnmarshalJSON
+// functions, and the methods that let encoding/json handle its
+// productions and product types. Non-terminals are sums, so their
+// values are encoded with a "type" member naming the production.
+func (L lang) json() string {
+	var b, funcs, enc, dec, methods strings.Builder
+
+	for _, n := range L.nodes() {
+		fmt.Fprintf(&enc, "case %v:\n", n.name)
+		if n.term {
+			fmt.Fprintf(&enc, "w.str(`{\"type\":%q,\"value\":`)\n", n.name)
+			L.jsonEncode(&enc, "n", nil, n.name)
+			fmt.Fprintf(&enc, "w.str(`}`)\n")
+			continue
+		}
+
+		sep := "{"
+		if !n.product {
+			fmt.Fprintf(&enc, "w.str(`{\"type\":%q`)\n", n.name)
+			sep = ","
+		}
+		for _, field := range n.fields {
+			fmt.Fprintf(&enc, "w.str(`%v%q:`)\n", sep, field.Name())
+			L.jsonEncode(&enc, "n."+field.Name(), field.Type(), "")
+			sep = ","
+		}
+		if sep == "{" {
+			fmt.Fprintf(&enc, "w.str(`{`)\n")
+		}
+		fmt.Fprintf(&enc, "w.str(`}`)\n")
+
+		fmt.Fprintf(&methods, "// MarshalJSON implements json.Marshaler, encoding n as MarshalJSON\n// does.\n")
+		fmt.Fprintf(&methods, "func (n %v) MarshalJSON() ([]byte, error) { return MarshalJSON(n) }\n\n", n.name)
+		fmt.Fprintf(&methods, "// UnmarshalJSON implements json.Unmarshaler.\n")
+		if n.product {
+			fmt.Fprintf(&methods, "func (n *%v) UnmarshalJSON(data []byte) (err error) {\n*n, err = unmarshal(data, decodeJSON%v)\nreturn err\n}\n\n", n.name, n.name)
+		} else {
+			fmt.Fprintf(&methods, "func (n *%v) UnmarshalJSON(data []byte) (err error) {\n*n, err = unmarshalAs[%v](data, decodeJSON%v)\nreturn err\n}\n\n", n.name, n.name, n.owner)
+		}
+	}
+
+	for _, defName := range keys(L.defs) {
+		nt, ok := L.defs[defName].(*nonterm)
+		if !ok {
+			continue
+		}
+
+		fmt.Fprintf(&funcs, "// UnmarshalJSON%v decodes a %v from data, in the format\n// written by MarshalJSON.\n", defName, defName)
+		fmt.Fprintf(&funcs, "func UnmarshalJSON%v(data []byte) (%v, error) { return unmarshal(data, decodeJSON%v) }\n\n", defName, defName, defName)
+
+		fmt.Fprintf(&dec, "\nfunc decodeJSON%v(data json.RawMessage) %v {\n", defName, defName)
+		if nt.str != nil {
+			n := node{name: defName, fields: structFields(nt.str), product: true}
+			fmt.Fprintf(&dec, "_, obj := jsonObject(data, %q, false)\nreturn %v\n}\n", defName, L.jsonFields(n))
+			continue
+		}
+
+		fmt.Fprintf(&dec, "if string(data) == \"null\" {\nreturn nil\n}\n")
+		fmt.Fprintf(&dec, "switch typ, obj := jsonObject(data, %q, true); typ {\n", defName)
+		for _, n := range L.members(defName) {
+			fmt.Fprintf(&dec, "case %q:\n", n.name)
+			if n.term {
+				fmt.Fprintf(&dec, "return %v\n", L.jsonDecode(`obj.get("value")`, nil, n.name))
+				continue
+			}
+			fmt.Fprintf(&dec, "return %v\n", L.jsonFields(n))
+		}
+		fmt.Fprintf(&dec, "default:\npanic(jsonErrorf(\"%%q does not belong to %v\", typ))\n}\n}\n", defName)
+	}
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	fmt.Fprintf(&b, "import (\n\"encoding/json\"\n\"fmt\"\n\n%q\n)\n\n", sexprPath)
+
+	fmt.Fprintf(&b, `// MarshalJSON encodes x as JSON. Values of non-terminals are objects
+// whose "type" member names their production, or terminal, whose value
+// is then their "value" member; values of product types are objects
+// without one. The other members hold the fields, by name. Terminals
+// are numbers, or strings in their Unparse notation if they're not
+// plain integers, and absent optional fields are null. Metadata isn't
+// encoded.
+func MarshalJSON(x Node) (data []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(jsonError)
+			if !ok {
+				panic(r)
+			}
+			err = e.error
+		}
+	}()
+	var w jsonWriter
+	w.node(x)
+	return w.buf, nil
+}
+
+%[1]v%[2]vtype jsonWriter struct {
+	buf []byte
+}
+
+func (w *jsonWriter) str(s string) {
+	w.buf = append(w.buf, s...)
+}
+
+func (w *jsonWriter) value(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(jsonError{err})
+	}
+	w.buf = append(w.buf, data...)
+}
+
+func (w *jsonWriter) node(x Node) {
+	switch n := x.(type) {
+	case nil:
+		w.str("null")
+%[3]v	default:
+		panic(jsonErrorf("cannot encode %%T", x))
+	}
+}
+
+// A jsonError is an error panicked by the JSON encoder and decoders,
+// and returned by their callers.
+type jsonError struct {
+	error
+}
+
+func jsonErrorf(format string, args ...any) jsonError {
+	return jsonError{fmt.Errorf("%[4]v: "+format, args...)}
+}
+
+func unmarshal[T any](data []byte, f func(json.RawMessage) T) (res T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(jsonError)
+			if !ok {
+				panic(r)
+			}
+			err = e.error
+		}
+	}()
+	return f(data), nil
+}
+
+// unmarshalAs decodes a value of the non-terminal read by f, which
+// must be a T.
+func unmarshalAs[T, N Node](data []byte, f func(json.RawMessage) N) (T, error) {
+	var zero T
+	x, err := unmarshal(data, f)
+	if err != nil {
+		return zero, err
+	}
+	res, ok := Node(x).(T)
+	if !ok {
+		return zero, jsonErrorf("expected %%T, found %%T", zero, x).error
+	}
+	return res, nil
+}
+
+type jsonFields map[string]json.RawMessage
+
+// jsonObject returns the members of the object in data, and its
+// "type" member if typed.
+func jsonObject(data json.RawMessage, want string, typed bool) (string, jsonFields) {
+	var obj jsonFields
+	if err := json.Unmarshal(data, &obj); err != nil || obj == nil {
+		panic(jsonErrorf("expected %%v object, found %%s", want, data))
+	}
+	if !typed {
+		return "", obj
+	}
+	var typ string
+	if err := json.Unmarshal(obj["type"], &typ); err != nil {
+		panic(jsonErrorf("%%v object has no type: %%s", want, data))
+	}
+	return typ, obj
+}
+
+func (obj jsonFields) get(name string) json.RawMessage {
+	data, ok := obj[name]
+	if !ok {
+		panic(jsonErrorf("missing %%q member", name))
+	}
+	return data
+}
+
+func jsonValue[T any](data json.RawMessage, want string) T {
+	var res T
+	if err := json.Unmarshal(data, &res); err != nil {
+		panic(jsonErrorf("expected %%v, found %%s", want, data))
+	}
+	return res
+}
+
+// jsonAtom reads a string from data and converts it with parse, which
+// reads a terminal from its s-expression notation.
+func jsonAtom[T any](data json.RawMessage, want string, parse func(sexpr.Expr) T) T {
+	s := jsonValue[string](data, want)
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(*sexpr.Error); !ok {
+				panic(r)
+			}
+			panic(jsonErrorf("expected %%v, found %%q", want, s))
+		}
+	}()
+	return parse(&sexpr.Atom{Text: s})
+}
+
+func jsonAll[T any](data json.RawMessage, f func(json.RawMessage) T) []T {
+	xs := jsonValue[[]json.RawMessage](data, "array")
+	if len(xs) == 0 {
+		return nil
+	}
+	res := make([]T, len(xs))
+	for i, x := range xs {
+		res[i] = f(x)
+	}
+	return res
+}
+
+func jsonOpt[T any](data json.RawMessage, f func(json.RawMessage) T) *T {
+	if string(data) == "null" {
+		return nil
+	}
+	res := f(data)
+	return &res
+}
+%[5]v`, funcs.String(), methods.String(), enc.String(), L.name, dec.String())
+
+	return b.String()
+}
+
+// jsonEncode writes the statements that encode x, an expression of
+// type typ, with the jsonWriter w. If termName is non-empty, it names
+// the terminal to encode instead.
+func (L lang) jsonEncode(b *strings.Builder, x string, typ types.Type, termName string) {
+	if tparam, ok := typ.(*types.TypeParam); ok {
+		termName = tparam.Obj().Name()
+		if _, ok := L.defs[termName].(*term); !ok {
+			fmt.Fprintf(b, "w.node(%v)\n", x)
+			return
+		}
+	}
+	if termName != "" {
+		if L.defs[termName].(*term).custom() {
+			fmt.Fprintf(b, "w.value(%v.String())\n", x)
+		} else {
+			fmt.Fprintf(b, "w.value(int64(%v))\n", x)
+		}
+		return
+	}
+
+	switch typ := typ.(type) {
+	case *types.Basic:
+		fmt.Fprintf(b, "w.value(%v)\n", x)
+	case *types.Slice:
+		fmt.Fprintf(b, "w.str(\"[\")\nfor i, x := range %v {\nif i > 0 {\nw.str(\",\")\n}\n", x)
+		L.jsonEncode(b, "x", typ.Elem(), "")
+		fmt.Fprintf(b, "}\nw.str(\"]\")\n")
+	case *types.Pointer:
+		fmt.Fprintf(b, "if %v == nil {\nw.str(\"null\")\n} else {\n", x)
+		L.jsonEncode(b, "*"+x, typ.Elem(), "")
+		fmt.Fprintf(b, "}\n")
+	default:
+		panic(fmt.Sprintf("unexpected field type %v", typ))
+	}
+}
+
+// jsonFields returns a composite literal of type n, which decodes its
+// fields from the members of obj.
+func (L lang) jsonFields(n node) string {
+	var fields []string
+	for _, field := range n.fields {
+		x := fmt.Sprintf("obj.get(%q)", field.Name())
+		fields = append(fields, fmt.Sprintf("%v: %v", field.Name(), L.jsonDecode(x, field.Type(), "")))
+	}
+	return fmt.Sprintf("%v{%v}", n.name, strings.Join(fields, ", "))
+}
+
+// jsonDecode returns an expression that decodes a value of type typ
+// from x, an expression of type json.RawMessage. If termName is
+// non-empty, it names the terminal to decode instead.
+func (L lang) jsonDecode(x string, typ types.Type, termName string) string {
+	if tparam, ok := typ.(*types.TypeParam); ok {
+		termName = tparam.Obj().Name()
+		if _, ok := L.defs[termName].(*term); !ok {
+			return fmt.Sprintf("decodeJSON%v(%v)", termName, x)
+		}
+	}
+	if termName != "" {
+		if L.defs[termName].(*term).custom() {
+			return fmt.Sprintf("jsonAtom(%v, %q, parse%v)", x, termName, termName)
+		}
+		return fmt.Sprintf("jsonValue[%v](%v, %q)", termName, x, termName)
+	}
+
+	switch typ := typ.(type) {
+	case *types.Basic:
+		return fmt.Sprintf("jsonValue[%v](%v, %q)", typ, x, typ)
+	case *types.Slice:
+		return fmt.Sprintf("jsonAll(%v, %v)", x, L.jsonFunc(typ.Elem()))
+	case *types.Pointer:
+		return fmt.Sprintf("jsonOpt(%v, %v)", x, L.jsonFunc(typ.Elem()))
+	}
+	panic(fmt.Sprintf("unexpected field type %v", typ))
+}
+
+// jsonFunc returns a function that decodes a value of type typ from a
+// json.RawMessage.
+func (L lang) jsonFunc(typ types.Type) string {
+	if tparam, ok := typ.(*types.TypeParam); ok {
+		if _, ok := L.defs[tparam.Obj().Name()].(*nonterm); ok {
+			return "decodeJSON" + tparam.Obj().Name()
+		}
+	}
+	t := types.TypeString(typ, nil)
+	return fmt.Sprintf("func(x json.RawMessage) %v { return %v }", t, L.jsonDecode("x", typ, ""))
+}
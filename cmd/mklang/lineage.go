@@ -0,0 +1,191 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// A passDecl is a pass, as declared by the comment at the top of its
+// file:
+//
+//	// pass: remove-one-armed-if : Lsrc -> L1
+type passDecl struct {
+	name     string
+	from, to string
+}
+
+var (
+	// passPrefix matches the start of a pass declaration.
+	passPrefix = regexp.MustCompile(`^// pass *:`)
+
+	// passComment matches a whole pass declaration.
+	passComment = regexp.MustCompile(`^// pass *: *(\S+) *: *(\S+) *-> *(\S+)$`)
+)
+
+// readPasses returns the passes declared by the files matching
+// pattern, in the order of their files.
+func readPasses(pattern string) []passDecl {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var passes []passDecl
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sc := bufio.NewScanner(f)
+		for line := 1; sc.Scan(); line++ {
+			text := strings.TrimSpace(sc.Text())
+			if !passPrefix.MatchString(text) {
+				continue
+			}
+			m := passComment.FindStringSubmatch(text)
+			if m == nil {
+				log.Fatalf("%v:%d: malformed pass comment; want \"// pass: name : from -> to\"", file, line)
+			}
+			passes = append(passes, passDecl{m[1], m[2], m[3]})
+		}
+		f.Close()
+		if err := sc.Err(); err != nil {
+			log.Fatalf("%v: %v", file, err)
+		}
+	}
+	return passes
+}
+
+// printLineage prints chain as a Graphviz graph, in which each
+// language is a node, and the edge to each language from the one it
+// extends is labeled with the non-terminals and productions that are
+// added (+), removed (-), or changed (~), and with the passes between
+// them. Other passes, such as those that skip languages, are drawn as
+// separate dashed edges, and languages that no pass produces or
+// consumes are highlighted.
+func printLineage(w io.Writer, chain []lang, passes []passDecl) {
+	used := make(map[string]bool)
+	for _, p := range passes {
+		for _, name := range []string{p.from, p.to} {
+			if !slices.ContainsFunc(chain, func(L lang) bool { return L.name == name }) {
+				log.Fatalf("pass %v translates unknown language %v", p.name, name)
+			}
+			used[name] = true
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph lineage {\n")
+	fmt.Fprintf(&b, "\tnode [shape=box, fontname=\"Helvetica\"];\n")
+	fmt.Fprintf(&b, "\tedge [fontname=\"Helvetica\", fontsize=10];\n")
+	for _, L := range chain {
+		fmt.Fprintf(&b, "\t%q", L.name)
+		if !used[L.name] {
+			fmt.Fprintf(&b, " [style=filled, fillcolor=\"#ffd966\", tooltip=\"no pass produces or consumes %v\"]", L.name)
+		}
+		fmt.Fprintf(&b, ";\n")
+	}
+
+	adjacent := make(map[passDecl]bool)
+	for _, L := range chain {
+		if L.parent == "" {
+			continue
+		}
+		L0 := parentOf(chain, L)
+		var label []string
+		for _, p := range passes {
+			if p.from == L0.name && p.to == L.name {
+				label = append(label, "pass "+p.name)
+				adjacent[p] = true
+			}
+		}
+		style := ""
+		if len(label) == 0 {
+			style = ", style=dashed, color=gray"
+		}
+		label = append(label, deltaSummary(L0, L)...)
+		fmt.Fprintf(&b, "\t%q -> %q [label=\"%v\"%v];\n", L0.name, L.name, dotLines(label), style)
+	}
+	for _, p := range passes {
+		if !adjacent[p] {
+			fmt.Fprintf(&b, "\t%q -> %q [label=\"%v\", style=dashed, color=blue, fontcolor=blue];\n", p.from, p.to, dotLines([]string{"pass " + p.name}))
+		}
+	}
+	fmt.Fprintf(&b, "}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// deltaSummary returns a line for each non-terminal or production that
+// L adds (+), removes (-), or changes (~) relative to L0. Productions
+// are named after their non-terminals, as in Expr.If, and aren't listed
+// separately when their non-terminal is added or removed.
+func deltaSummary(L0, L lang) []string {
+	names := make(map[string]bool)
+	for defName, def := range L0.defs {
+		if _, ok := def.(*nonterm); ok {
+			names[defName] = true
+		}
+	}
+	for defName, def := range L.defs {
+		if _, ok := def.(*nonterm); ok {
+			names[defName] = true
+		}
+	}
+
+	var res []string
+	for _, defName := range keys(names) {
+		nt0, _ := L0.defs[defName].(*nonterm)
+		nt, _ := L.defs[defName].(*nonterm)
+		switch {
+		case nt0 == nil:
+			res = append(res, "+"+defName)
+		case nt == nil:
+			res = append(res, "-"+defName)
+		case (nt0.str != nil) != (nt.str != nil):
+			res = append(res, "~"+defName)
+		case nt.str != nil:
+			if fieldsSig(structFields(nt0.str)) != fieldsSig(structFields(nt.str)) {
+				res = append(res, "~"+defName)
+			}
+		default:
+			for _, conName := range keys(nt0.cons) {
+				if nt.cons[conName] == nil {
+					res = append(res, "-"+defName+"."+conName)
+				}
+			}
+			for _, conName := range keys(nt.cons) {
+				con0, con := nt0.cons[conName], nt.cons[conName]
+				switch {
+				case con0 == nil:
+					res = append(res, "+"+defName+"."+conName)
+				case conSig(con0) != conSig(con):
+					res = append(res, "~"+defName+"."+conName)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// dotLines returns lines as the text of a left-justified DOT label.
+func dotLines(lines []string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(line))
+		b.WriteString(`\l`)
+	}
+	return b.String()
+}
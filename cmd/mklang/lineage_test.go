@@ -0,0 +1,61 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadPasses(t *testing.T) {
+	got := readPasses(filepath.Join("..", "..", "example", "passes", "*.go"))
+	want := []passDecl{
+		{"convert-assignments", "L9", "L10"},
+		{"identify-assigned-variables", "L6", "L7"},
+		{"inverse-eta-raw-primitives", "L3", "L4"},
+		{"make-being-explicit", "L2", "L3"},
+		{"optimize-direct-call", "L8", "L8"},
+		{"purify-letrec", "L7", "L8"},
+		{"quote-constants", "L4", "L5"},
+		{"remove-and-or-not", "L1", "L2"},
+		{"remove-anonymous-lambda", "L8", "L9"},
+		{"remove-complex-constants", "L5", "L6"},
+		{"remove-one-armed-if", "Lsrc", "L1"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("readPasses found\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestPassComment(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"// pass: a : L1 -> L2", []string{"a", "L1", "L2"}},
+		{"// pass : a : L1 -> L2", []string{"a", "L1", "L2"}},
+		{"// pass:a:L1->L2", []string{"a", "L1", "L2"}},
+		{"// pass: a L1 -> L2", nil},
+		{"// pass: a : L1", nil},
+	}
+	for _, tt := range tests {
+		if !passPrefix.MatchString(tt.text) {
+			t.Errorf("%q is not a pass declaration", tt.text)
+		}
+		var got []string
+		if m := passComment.FindStringSubmatch(tt.text); m != nil {
+			got = m[1:]
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q declares %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	// Comments that merely start with "pass" aren't declarations.
+	if text := "// passes those arguments"; passPrefix.MatchString(text) {
+		t.Errorf("%q is a pass declaration", text)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// A language may declare a metadata field, which is added to each of
+// its productions and product types:
+//
+//	Meta meta                     // lang.Meta[any]
+//	Meta interface{ meta; Note }  // lang.Meta[Note]
+//
+// Later languages inherit the field, unless they omit it. The field
+// holds a node's source span, which Parse fills in, and an annotation
+// for passes to use. The generated Translator carries it across the
+// 1:1 translations that a pass leaves to it, and the generated WithMeta
+// function across the translations that a pass writes itself.
+
+// A metaDecl is the declaration of a language's metadata field.
+type metaDecl struct {
+	name string
+	note types.Type // nil for any
+	pass string     // language that declared it
+	pos  token.Pos
+}
+
+// String returns the name and type of the field, or "" if m is nil.
+func (m *metaDecl) String() string {
+	if m == nil {
+		return ""
+	}
+	note := "any"
+	if m.note != nil {
+		note = types.TypeString(m.note, (*types.Package).Name)
+	}
+	return m.name + " lang.Meta[" + note + "]"
+}
+
+// declMeta returns the metadata field declared by tparam, whose
+// constraint is meta, or an interface embedding meta and the
+// annotation type.
+func (L lang) declMeta(tparam *types.TypeParam) *metaDecl {
+	m := &metaDecl{name: tparam.Obj().Name(), pass: L.name, pos: tparam.Obj().Pos()}
+	iface, ok := tparam.Constraint().(*types.Interface)
+	if !ok {
+		return m
+	}
+	if iface.NumMethods() != 0 {
+		errorf(m.pos, "metadata field %v cannot have methods", m.name)
+	}
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		switch typ := iface.EmbeddedType(i); {
+		case isKeyword(typ, "meta"):
+		case m.note != nil:
+			errorf(m.pos, "metadata field %v has more than one annotation type: %v and %v", m.name, m.note, typ)
+		default:
+			m.note = typ
+		}
+	}
+	return m
+}
+
+// checkMeta reports an error if one of L's productions or product
+// types has a field that would collide with its metadata field or its
+// Pos method.
+func (L lang) checkMeta() {
+	if L.meta == nil {
+		return
+	}
+	if L.meta.name == "Pos" {
+		errorf(L.meta.pos, "metadata field %v collides with the Pos method", L.meta.name)
+	}
+	if L.defs[L.meta.name] != nil {
+		errorf(L.meta.pos, "metadata field %v has the same name as a definition of %v", L.meta.name, L.name)
+	}
+	for _, n := range L.nodes() {
+		for _, field := range n.fields {
+			if name := field.Name(); name == L.meta.name || name == "Pos" {
+				errorf(field.Pos(), "field %v of %v collides with the metadata of %v", name, n.name, L.name)
+			}
+		}
+	}
+}
+
+// metaType returns the type of L's metadata field, adding the import
+// paths it needs to imports.
+func (L lang) metaType(imports map[string]bool) string {
+	imports[langPath] = true
+	note := "any"
+	if L.meta.note != nil {
+		note = qualify(L.meta.note, imports)
+	}
+	return "lang.Meta[" + note + "]"
+}
+
+// metaFuncs returns the source for the Pos methods of L's productions
+// and product types, and its MetaOf and WithMeta functions.
+func (L lang) metaFuncs() string {
+	var b, pos, gets, sets strings.Builder
+
+	imports := map[string]bool{sexprPath: true}
+	typ := L.metaType(imports)
+
+	for _, n := range L.nodes() {
+		if n.term {
+			continue
+		}
+		fmt.Fprintf(&pos, "// Pos returns the position of the source text that n was parsed\n// from, if it's known.\n")
+		fmt.Fprintf(&pos, "func (n %v) Pos() sexpr.Pos { return n.%v.Span.Start }\n\n", n.name, L.meta.name)
+		fmt.Fprintf(&gets, "case %v:\nreturn n.%v\n", n.name, L.meta.name)
+		fmt.Fprintf(&sets, "case %v:\nn.%v = m\nreturn Node(n).(T)\n", n.name, L.meta.name)
+	}
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	b.WriteString(importDecl(nil, imports))
+
+	b.WriteString(pos.String())
+
+	fmt.Fprintf(&b, `// MetaOf returns the metadata of x, which is empty for terminals.
+func MetaOf(x Node) %[1]v {
+	switch n := x.(type) {
+%[2]v}
+	return %[1]v{}
+}
+
+// WithMeta returns a copy of x with metadata m. A pass that builds a
+// node of the next language itself, rather than leaving it to the
+// Translator, uses it to carry the metadata along:
+//
+//	return WithMeta(If{Cond: cond, Then: then, Else: els}, src.MetaOf(n))
+//
+// Terminals have no metadata, so they're returned unchanged.
+func WithMeta[T Node](x T, m %[1]v) T {
+	switch n := Node(x).(type) {
+%[3]v}
+	return x
+}
+`, typ, gets.String(), sets.String())
+
+	return b.String()
+}
@@ -6,11 +6,15 @@ package main
 
 import (
 	"cmp"
+	"flag"
 	"fmt"
+	"go/ast"
 	"go/format"
+	"go/token"
 	"go/types"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -18,53 +22,371 @@ import (
 	"golang.org/x/tools/go/packages"
 )
 
+var (
+	checkFlag   = flag.Bool("check", false, "check that the generated packages are up to date, instead of writing them")
+	pkgFlag     = flag.String("pkg", ".", "`pattern` of the package containing the language declarations")
+	outFlag     = flag.String("o", "lang", "`directory` to write the generated packages into")
+	pkgNameFlag = flag.String("pkgname", "%s", "`format` for the generated package names, where %s is the language name")
+	markerFlag  = flag.String("marker", "language", "`type` used to mark a generic type declaration as a language")
+	strictFlag  = flag.Bool("strict", false, "treat warnings, such as redundant omits, as errors")
+	deltaFlag   = flag.Bool("delta", false, "print the changes between two languages, or between each language and the one it extends, instead of generating packages")
+	htmlFlag    = flag.Bool("html", false, "with -delta, print HTML instead of Markdown")
+	schemeFlag  = flag.String("scheme", "", "print the languages as nanopass define-language forms, either in `full` or as extends deltas, instead of generating packages")
+	lineageFlag = flag.Bool("lineage", false, "print the tree of languages, with the changes and passes between them, as a Graphviz graph instead of generating packages")
+	passesFlag  = flag.String("passes", "passes/*.go", "with -lineage, `pattern` of the files declaring passes in \"// pass: name : from -> to\" comments")
+)
+
 func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: mklang [flags]\n")
+		fmt.Fprintf(os.Stderr, "       mklang -delta [flags] [from to]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if n := flag.NArg(); n != 0 && !(*deltaFlag && n == 2) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *schemeFlag != "" && *schemeFlag != "full" && *schemeFlag != "extends" {
+		log.Fatalf("invalid -scheme %q; want full or extends", *schemeFlag)
+	}
+
+	fset = token.NewFileSet()
 	cfg := packages.Config{
-		Mode: packages.NeedTypes,
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedModule,
+		Fset: fset,
 	}
-	pkgs, err := packages.Load(&cfg, ".")
+	pkgs, err := packages.Load(&cfg, *pkgFlag)
 	if err != nil {
 		log.Fatal(err)
 	}
-	pkg := pkgs[0].Types
-	scope := pkg.Scope()
+	if packages.PrintErrors(pkgs) > 0 {
+		os.Exit(1)
+	}
+	if len(pkgs) != 1 {
+		log.Fatalf("package pattern %q matched %d packages; want 1", *pkgFlag, len(pkgs))
+	}
 
-	var langs []*types.Named
-	for _, name := range scope.Names() {
-		if !strings.HasPrefix(name, "L") {
-			continue
+	base := *outFlag
+	chain, files := build(pkgs[0], base)
+
+	flushDiagnostics()
+
+	if *deltaFlag {
+		printDelta(chain, flag.Args())
+		return
+	}
+
+	if *schemeFlag != "" {
+		printScheme(os.Stdout, chain, *schemeFlag == "extends")
+		return
+	}
+
+	if *lineageFlag {
+		printLineage(os.Stdout, chain, readPasses(*passesFlag))
+		return
+	}
+
+	if *checkFlag {
+		if !check(base, files) {
+			os.Exit(1)
 		}
-		lang := scope.Lookup(name).(*types.TypeName).Type().(*types.Named)
-		langs = append(langs, lang)
+		return
+	}
+
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0777); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(f.path, f.src, 0666); err != nil {
+			log.Fatal(err)
+		}
+	}
+	old, err := stale(files)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, path := range old {
+		if err := os.Remove(path); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// build builds the languages declared by pkg, in the order in which
+// they extend each other, and generates the files of their packages
+// under base. Problems with the declarations are reported as
+// diagnostics, and once there are any errors, no more files are
+// generated.
+func build(pkg *packages.Package, base string) ([]lang, []file) {
+	langs := languages(pkg)
+	if len(langs) == 0 {
+		flushDiagnostics()
+		log.Fatalf("%v declares no languages (generic types defined as %v)", pkg.PkgPath, *markerFlag)
 	}
 
 	slices.SortFunc(langs, func(li, lj *types.Named) int {
 		return cmp.Compare(li.Obj().Pos(), lj.Obj().Pos())
 	})
+	langs, parents := resolveParents(langs)
+
+	// Generate everything in memory first, so that nothing is written
+	// if there are any errors.
+	var files []file
+	var chain []lang
+	built := make(map[*types.Named]lang)
+	for _, l := range langs {
+		P := built[parents[l]]
+		L := P.extend(l.Obj().Name(), l.Obj().Pos(), l.TypeParams())
+		L.pkg = fmt.Sprintf(*pkgNameFlag, L.name)
+		if !token.IsIdentifier(L.pkg) {
+			errorf(l.Obj().Pos(), "invalid package name %q for %v", L.pkg, L.name)
+		}
+		dir := filepath.Join(base, L.pkg)
+		L.path = importPath(pkg, dir)
+		L.heads()
+		built[l] = L
+		chain = append(chain, L)
+		if hasErrors() {
+			// Keep checking the remaining languages, but don't
+			// bother generating code for them.
+			continue
+		}
+
+		files = append(files,
+			generate(dir, L.pkg+".go", L.String()),
+			generate(dir, "walk.go", L.walk()),
+			generate(dir, "format.go", L.format()),
+			generate(dir, "parse.go", L.parse()),
+			generate(dir, "desc.go", L.desc()),
+			generate(dir, "doc.go", L.grammar()),
+			generate(dir, "equal.go", L.equal()),
+			generate(dir, "clone.go", L.clone()),
+			generate(dir, "json.go", L.json()),
+			generate(dir, "binary.go", L.binary()),
+			generate(dir, "dot.go", L.dot()),
+		)
+		if L.vars != "" {
+			files = append(files, generate(dir, "bind.go", L.bind()))
+		}
+		if L.meta != nil {
+			files = append(files, generate(dir, "meta.go", L.metaFuncs()))
+		}
+		if P.name != "" {
+			files = append(files, generate(dir, "translate.go", L.translate(P)))
+		}
+	}
+	return chain, files
+}
+
+// importPath returns the import path of the package in dir, which
+// must be within the module containing pkg. If pkg isn't in a module,
+// dir is taken to be relative to pkg's directory.
+func importPath(pkg *packages.Package, dir string) string {
+	m := pkg.Module
+	if m == nil {
+		return path.Join(pkg.PkgPath, filepath.ToSlash(dir))
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rel, err := filepath.Rel(m.Dir, abs)
+	if err != nil || !filepath.IsLocal(rel) {
+		log.Fatalf("output directory %v is outside module %v", abs, m.Path)
+	}
+	return path.Join(m.Path, filepath.ToSlash(rel))
+}
 
-	base := "lang"
-	os.MkdirAll(base, 0777)
+// printDelta prints a report of the changes between the named pair
+// of languages, if any, or else between each language in chain and
+// the one it extends, starting with the full contents of the first.
+func printDelta(chain []lang, names []string) {
+	var reports []report
+	if len(names) == 2 {
+		var pair [2]lang
+		for i, name := range names {
+			j := slices.IndexFunc(chain, func(L lang) bool { return L.name == name })
+			if j < 0 {
+				log.Fatalf("unknown language %v", name)
+			}
+			pair[i] = chain[j]
+		}
+		reports = append(reports, delta(pair[0], pair[1]))
+	} else {
+		for _, L := range chain {
+			reports = append(reports, delta(parentOf(chain, L), L))
+		}
+	}
 
-	var L lang
+	if *htmlFlag {
+		writeHTML(os.Stdout, reports)
+	} else {
+		writeMarkdown(os.Stdout, reports)
+	}
+}
+
+// languages returns the languages declared by pkg: the generic type
+// declarations whose definition is the marker type. It reports an
+// error if the marker isn't a type declared by pkg, or if a type
+// declaration without type parameters is defined as the marker.
+func languages(pkg *packages.Package) []*types.Named {
+	if _, ok := pkg.Types.Scope().Lookup(*markerFlag).(*types.TypeName); !ok {
+		errorf(token.NoPos, "marker %v is not a type declared in %v", *markerFlag, pkg.PkgPath)
+		return nil
+	}
+	var res []*types.Named
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if id, ok := spec.Type.(*ast.Ident); !ok || id.Name != *markerFlag || spec.Assign.IsValid() {
+					continue
+				}
+				if spec.TypeParams == nil {
+					errorf(spec.Name.Pos(), "%v is defined as %v, but isn't generic; a language's definitions are its type parameters", spec.Name.Name, *markerFlag)
+					continue
+				}
+				obj := pkg.Types.Scope().Lookup(spec.Name.Name)
+				res = append(res, obj.Type().(*types.Named))
+			}
+		}
+	}
+	return res
+}
+
+// resolveParents returns langs, which are in source order, reordered
+// so that each language follows its parent, the language it extends,
+// and the parent of each language but the first. A language extends
+// the one declared before it, unless it names another, as in
+// "L8 extends". Languages whose parents are missing or that extend
+// themselves, directly or not, are reported and left out, along with
+// their descendants.
+func resolveParents(langs []*types.Named) ([]*types.Named, map[*types.Named]*types.Named) {
+	byName := make(map[string]*types.Named)
 	for _, l := range langs {
-		L = L.extend(l.Obj().Name(), l.TypeParams())
+		byName[l.Obj().Name()] = l
+	}
 
-		dir := filepath.Join(base, L.name)
-		os.Mkdir(dir, 0777)
+	parents := make(map[*types.Named]*types.Named)
+	ok := make(map[*types.Named]bool)
+	for i, l := range langs {
+		if i > 0 {
+			parents[l] = langs[i-1]
+		}
+		ok[l] = true
+		explicit := false
+		tparams := l.TypeParams()
+		for j := 0; j < tparams.Len(); j++ {
+			tparam := tparams.At(j)
+			if !isKeyword(tparam.Constraint(), "extends") {
+				continue
+			}
+			name := tparam.Obj().Name()
+			switch parent := byName[name]; {
+			case explicit:
+				errorf(tparam.Obj().Pos(), "%v already extends %v", l.Obj().Name(), parents[l].Obj().Name())
+			case parent == nil:
+				errorf(tparam.Obj().Pos(), "%v extends %v, which is not a language", l.Obj().Name(), name)
+				ok[l] = false
+			default:
+				parents[l] = parent
+			}
+			explicit = true
+		}
+	}
 
-		buf := []byte(L.String())
-		if buf1, err := format.Source(buf); err != nil {
-			fmt.Printf("%v: format error: %v\n", L.name, err)
-		} else {
-			buf = buf1
+	var order []*types.Named
+	const visiting, visited = 1, 2
+	state := make(map[*types.Named]int)
+	var visit func(l *types.Named, path []string) bool
+	visit = func(l *types.Named, path []string) bool {
+		path = append(path, l.Obj().Name())
+		switch state[l] {
+		case visiting:
+			i := slices.Index(path, l.Obj().Name())
+			errorf(l.Obj().Pos(), "language cycle: %v", strings.Join(path[i:], " extends "))
+			ok[l] = false
+			return false
+		case visited:
+			return ok[l]
+		}
+		state[l] = visiting
+		if parent := parents[l]; parent != nil && !visit(parent, path) {
+			ok[l] = false
+		}
+		state[l] = visited
+		if ok[l] {
+			order = append(order, l)
+		}
+		return ok[l]
+	}
+	for _, l := range langs {
+		visit(l, nil)
+	}
+	return order, parents
+}
+
+// parentOf returns the language in chain that L extends, or the zero
+// lang if it extends none.
+func parentOf(chain []lang, L lang) lang {
+	for _, L0 := range chain {
+		if L0.name == L.parent {
+			return L0
 		}
-		os.WriteFile(filepath.Join(dir, L.name+".go"), buf, 0666)
 	}
+	return lang{}
+}
+
+// A file is a generated source file.
+type file struct {
+	path string
+	src  []byte
+}
+
+// generate formats the generated source src for the named file
+// within dir.
+func generate(dir, name, src string) file {
+	path := filepath.Join(dir, name)
+	buf, err := format.Source([]byte(src))
+	if err != nil {
+		errorf(token.NoPos, "%v: format error: %v", path, err)
+		buf = []byte(src)
+	}
+	return file{path, buf}
 }
 
 type lang struct {
-	name string
-	defs map[string]Define
+	name   string
+	pos    token.Pos
+	pkg    string // generated package name
+	path   string // import path of the generated package
+	parent string // name of the language that L extends, if any
+	defs   map[string]Define
+
+	// entry is the name of the entry non-terminal, if any.
+	entry string
+
+	// vars is the name of the terminal bound by the language's
+	// binding fields, if any.
+	vars string
+
+	// meta is the language's metadata field, if any.
+	meta *metaDecl
+
+	// codes maps the names of primitives to their values, which are
+	// shared by the whole chain of languages, so that they don't
+	// change when a primitive table is redefined or split.
+	codes map[string]int
+
+	// gone maps the names of productions, terminals, and product
+	// types that were present in an earlier language, but are absent
+	// from this one, to the name of the language that removed them.
+	gone map[string]string
 }
 
 type Define interface {
@@ -73,19 +395,31 @@ type Define interface {
 
 type term struct {
 	pass   string
+	pos    token.Pos
+	repr   types.Type       // Go representation; nil for the default, int
+	prims  map[string]*prim // primitives, if it's a primitive table
 	isAlso map[string]bool
 }
 
 func (t term) copy() Define { t.isAlso = nil; return &t }
 
 type nonterm struct {
+	pass   string
+	pos    token.Pos
 	embeds map[string]bool
-	cons   map[string]*types.Signature
+	cons   map[string]*types.Func
+	from   map[string]string // constructor name -> introducing pass
 	str    *types.Struct
 	isAlso map[string]bool
 }
 
-func (t nonterm) copy() Define { dup(&t.embeds); dup(&t.cons); t.isAlso = nil; return &t }
+func (t nonterm) copy() Define {
+	dup(&t.embeds)
+	dup(&t.cons)
+	dup(&t.from)
+	t.isAlso = nil
+	return &t
+}
 
 func dup[K comparable, V any](mp *map[K]V) {
 	m := make(map[K]V, len(*mp))
@@ -95,62 +429,107 @@ func dup[K comparable, V any](mp *map[K]V) {
 	*mp = m
 }
 
-func (L0 lang) extend(langName string, tparams *types.TypeParamList) (L lang) {
+func (L0 lang) extend(langName string, pos token.Pos, tparams *types.TypeParamList) (L lang) {
 	L.name = langName
+	L.pos = pos
+	L.parent = L0.name
+	L.entry = L0.entry
+	L.meta = L0.meta
+	L.codes = L0.codes
+	if L.codes == nil {
+		L.codes = make(map[string]int)
+	}
 
 	L.defs = make(map[string]Define, len(L0.defs))
 	for defName, def := range L0.defs {
 		L.defs[defName] = def.copy()
 	}
 
-	commands := make(map[string][]string)
+	commands := make(map[string][]*types.TypeParam)
 	var delta []*types.TypeParam
 
 	for i := 0; i < tparams.Len(); i++ {
 		tparam := tparams.At(i)
-		name := tparam.Obj().Name()
 		switch typ := tparam.Constraint().(type) {
 		case *types.Named:
 			command := typ.Obj().Name()
-			commands[command] = append(commands[command], name)
+			commands[command] = append(commands[command], tparam)
 
 		case *types.Interface:
+			if command, ok := ifaceCommand(typ); ok {
+				commands[command] = append(commands[command], tparam)
+				continue
+			}
 			delta = append(delta, tparam)
 
 		default:
-			panic("unknown")
+			errorf(tparam.Obj().Pos(), "unexpected constraint for %v: %v", tparam.Obj().Name(), typ)
 		}
 	}
 
-	take := func(key string) []string {
+	take := func(key string) []*types.TypeParam {
 		res := commands[key]
 		delete(commands, key)
 		return res
 	}
 
-	for _, defName := range take("inherit") {
-		if L.defs[defName] == nil {
-			fmt.Printf("cannot inherit undefined %v\n", defName)
+	// The parent was already resolved by resolveParents.
+	take("extends")
+
+	for _, tparam := range take("inherit") {
+		if defName := tparam.Obj().Name(); L.defs[defName] == nil {
+			errorf(tparam.Obj().Pos(), "cannot inherit undefined %v", defName)
+		}
+	}
+
+	// declared maps the names of the definitions that L declares or
+	// changes to their positions.
+	declared := make(map[string]token.Pos)
+
+	for i, tparam := range take("meta") {
+		if i > 0 {
+			errorf(tparam.Obj().Pos(), "%v already has metadata field %v", L.name, L.meta.name)
+			continue
 		}
+		L.meta = L.declMeta(tparam)
 	}
 
-	for _, defName := range take("define") {
-		if L.defs[defName] == nil {
-			L.defs[defName] = &term{pass: L.name}
+	var entries []*types.TypeParam
+	for _, tparam := range take("entry") {
+		if _, ok := L.defs[tparam.Obj().Name()].(*nonterm); !ok {
+			errorf(tparam.Obj().Pos(), "entry %v is not a defined non-terminal", tparam.Obj().Name())
+			continue
+		}
+		entries = append(entries, tparam)
+	}
+
+	for _, tparam := range take("define") {
+		if defName := tparam.Obj().Name(); L.defs[defName] == nil {
+			L.defs[defName] = L.declTerm(tparam, nil)
+			declared[defName] = tparam.Obj().Pos()
 		} else {
-			fmt.Printf("%v is already defined\n", defName)
+			errorf(tparam.Obj().Pos(), "%v is already defined", defName)
 		}
 	}
 
-	for _, defName := range take("redefine") {
-		if _, ok := L.defs[defName].(*term); ok {
-			L.defs[defName] = &term{pass: L.name}
+	for _, tparam := range take("redefine") {
+		if defName := tparam.Obj().Name(); L.defs[defName] == nil {
+			errorf(tparam.Obj().Pos(), "cannot redefine undefined %v", defName)
+		} else if def, ok := L.defs[defName].(*term); ok {
+			L.defs[defName] = L.declTerm(tparam, def)
+			declared[defName] = tparam.Obj().Pos()
 		} else {
-			fmt.Printf("%v is not a defined non-terminal\n", defName)
+			errorf(tparam.Obj().Pos(), "cannot redefine %v, which is not a terminal", defName)
 		}
 	}
 
-	var embedClobbers, consClobbers []string
+	// Productions are Go types, so their names are unique within a
+	// language. Any constructor mentioned in the delta is removed from
+	// whichever non-terminal had it before, so that it moves.
+	//
+	// Embeddings, on the other hand, are kept unless explicitly
+	// removed, even when a non-terminal's constructors change.
+	var consClobbers []string
 	for _, typ := range delta {
 		iface := typ.Constraint().(*types.Interface)
 		if iface.IsImplicit() {
@@ -158,21 +537,6 @@ func (L0 lang) extend(langName string, tparams *types.TypeParamList) (L lang) {
 			continue
 		}
 
-		for i := 0; i < iface.NumEmbeddeds(); i++ {
-			embedType := iface.EmbeddedType(i)
-			ptrs := 0
-			for {
-				ptr, ok := embedType.(*types.Pointer)
-				if !ok {
-					break
-				}
-				ptrs++
-				embedType = ptr.Elem()
-			}
-			embed := embedType.(*types.TypeParam)
-			embedClobbers = append(embedClobbers, embed.Obj().Name())
-		}
-
 		for i := 0; i < iface.NumMethods(); i++ {
 			method := iface.Method(i)
 			consClobbers = append(consClobbers, method.Name())
@@ -182,11 +546,9 @@ func (L0 lang) extend(langName string, tparams *types.TypeParamList) (L lang) {
 	for _, def := range L.defs {
 		switch def := def.(type) {
 		case *nonterm:
-			for _, name := range embedClobbers {
-				delete(def.embeds, name)
-			}
 			for _, name := range consClobbers {
 				delete(def.cons, name)
+				delete(def.from, name)
 			}
 		}
 	}
@@ -198,42 +560,52 @@ func (L0 lang) extend(langName string, tparams *types.TypeParamList) (L lang) {
 		switch def := L.defs[defName].(type) {
 		case nil:
 			nt = &nonterm{
+				pass:   L.name,
 				embeds: make(map[string]bool),
-				cons:   make(map[string]*types.Signature),
+				cons:   make(map[string]*types.Func),
+				from:   make(map[string]string),
 			}
 			L.defs[defName] = nt
 		case *nonterm:
 			nt = def
 		case *term:
-			fmt.Printf("%v is already a terminal\n", defName)
+			errorf(typ.Obj().Pos(), "%v is already a terminal", defName)
+			continue
 		}
+		nt.pos = typ.Obj().Pos()
+		declared[defName] = nt.pos
 
 		iface := typ.Constraint().(*types.Interface)
 		if iface.IsImplicit() {
 			if nt.str != nil {
 				// TODO(mdempsky): This is a redefinition. Should this require special syntax?
-				// fmt.Printf("%v is already a product type\n", name)
 			}
-			nt.str = iface.EmbeddedType(0).(*types.Struct)
+			nt.str = unwrapStruct(iface.EmbeddedType(0).(*types.Struct))
+			nt.pass = L.name
+			for i := 0; i < nt.str.NumFields(); i++ {
+				checkField(nt.str.Field(i))
+			}
 			continue
 		}
 
 		for i := 0; i < iface.NumEmbeddeds(); i++ {
-			typ := iface.EmbeddedType(i)
-			ptrs := 0
-			for {
-				ptr, ok := typ.(*types.Pointer)
-				if !ok {
-					break
+			if named, ok := iface.EmbeddedType(i).(*types.Named); ok && named.Obj().Name() == "entry" {
+				entries = append(entries, typ)
+				continue
+			}
+			if embed, ok := unembed(iface.EmbeddedType(i)); ok {
+				if !nt.embeds[embed] {
+					warnf(typ.Obj().Pos(), "redundant omit: %v does not embed %v", defName, embed)
 				}
-				typ = ptr.Elem()
-				ptrs++
+				delete(nt.embeds, embed)
+				continue
 			}
-			tparam := typ.(*types.TypeParam)
-			embedName := tparam.Obj().Name()
-			if ptrs == 1 {
-				nt.embeds[embedName] = true
+			embed, ptrs := unptr(iface.EmbeddedType(i))
+			if embed == nil || ptrs != 1 {
+				errorf(typ.Obj().Pos(), "%v embeds %v, which is not a pointer to a terminal or non-terminal", defName, iface.EmbeddedType(i))
+				continue
 			}
+			nt.embeds[embed.Obj().Name()] = true
 		}
 
 		for i := 0; i < iface.NumMethods(); i++ {
@@ -242,17 +614,84 @@ func (L0 lang) extend(langName string, tparams *types.TypeParamList) (L lang) {
 			sig := con.Type().(*types.Signature)
 
 			if res := sig.Results(); res.Len() != 0 {
-				if res.Len() != 1 || res.At(0).Type().(*types.Named).Obj().Name() != "omit" {
-					fmt.Printf("unexpected signature result: %v\n", res)
+				if named, ok := res.At(0).Type().(*types.Named); res.Len() != 1 || !ok || named.Obj().Name() != "omit" {
+					errorf(con.Pos(), "unexpected result for %v: %v", conName, res)
 				}
 			} else {
-				nt.cons[conName] = sig
+				con = unwrapCon(con)
+				sig = con.Type().(*types.Signature)
+				nt.cons[conName] = con
+				nt.from[conName] = L.name
+				for i := 0; i < sig.Params().Len(); i++ {
+					checkField(sig.Params().At(i))
+				}
 			}
 		}
+
+		if nt.str != nil && (len(nt.cons) != 0 || len(nt.embeds) != 0) {
+			errorf(typ.Obj().Pos(), "%v is both a set and product type", defName)
+		}
 	}
 
-	for _, name := range take("omit") {
-		delete(L.defs, name)
+	for i, tparam := range entries {
+		if i > 0 {
+			errorf(tparam.Obj().Pos(), "%v already has entry %v", L.name, L.entry)
+			continue
+		}
+		L.entry = tparam.Obj().Name()
+	}
+
+	// An explicit omit is redundant if the definition is already
+	// absent, or would be pruned anyway.
+	var reached map[string]bool
+	if L.entry != "" {
+		reached = L.reachable(L.entry)
+	}
+	for _, tparam := range take("omit") {
+		defName := tparam.Obj().Name()
+		if L.meta != nil && defName == L.meta.name {
+			L.meta = nil
+			continue
+		}
+		switch {
+		case L.defs[defName] == nil:
+			warnf(tparam.Obj().Pos(), "redundant omit: %v is not defined", defName)
+		case reached != nil && !reached[defName]:
+			warnf(tparam.Obj().Pos(), "redundant omit: %v is unreachable from entry %v", defName, L.entry)
+		}
+		delete(L.defs, defName)
+	}
+
+	// Prune any definitions that are no longer reachable from the
+	// entry. Warn about ones declared by this language, as they're
+	// left dangling.
+	if L.entry != "" {
+		if _, ok := L.defs[L.entry].(*nonterm); !ok {
+			errorf(L.pos, "entry %v is not defined in %v", L.entry, L.name)
+		} else {
+			reached := L.reachable(L.entry)
+			for _, defName := range keys(L.defs) {
+				if reached[defName] {
+					continue
+				}
+				if pos := declared[defName]; pos.IsValid() {
+					warnf(pos, "%v is unreachable from entry %v in %v", defName, L.entry, L.name)
+				}
+				delete(L.defs, defName)
+			}
+		}
+	}
+
+	// Drop embeddings of definitions that were omitted or pruned, so
+	// that they're not revived if a later language redefines them.
+	for _, def := range L.defs {
+		if nt, ok := def.(*nonterm); ok {
+			for embed := range nt.embeds {
+				if L.defs[embed] == nil {
+					delete(nt.embeds, embed)
+				}
+			}
+		}
 	}
 
 	var flood func(nt *nonterm, also string)
@@ -284,13 +723,279 @@ func (L0 lang) extend(langName string, tparams *types.TypeParamList) (L lang) {
 		}
 	}
 
-	if len(commands) != 0 {
-		fmt.Printf("unknown commands: %v\n", commands)
+	for command, tparams := range commands {
+		for _, tparam := range tparams {
+			errorf(tparam.Obj().Pos(), "unknown command %v for %v", command, tparam.Obj().Name())
+		}
+	}
+
+	for _, n := range L.nodes() {
+		for _, field := range n.fields {
+			L.checkRefs(field, field.Type())
+		}
+	}
+	L.vars = L.checkBinders()
+	L.checkMeta()
+
+	L.gone = make(map[string]string, len(L0.gone))
+	for name, langName := range L0.gone {
+		L.gone[name] = langName
+	}
+	for _, n := range L0.nodes() {
+		L.gone[n.name] = L.name
+	}
+	for _, n := range L.nodes() {
+		delete(L.gone, n.name)
 	}
 
 	return
 }
 
+// reachable returns the set of terminals and non-terminals that are
+// reachable from the named definition, including itself, by way of
+// embeddings and fields.
+func (L lang) reachable(defName string) map[string]bool {
+	res := make(map[string]bool)
+	var visit func(defName string)
+	var visitType func(typ types.Type)
+	visit = func(defName string) {
+		if res[defName] || L.defs[defName] == nil {
+			return
+		}
+		res[defName] = true
+		if nt, ok := L.defs[defName].(*nonterm); ok {
+			for embed := range nt.embeds {
+				visit(embed)
+			}
+			if nt.str != nil {
+				for _, field := range structFields(nt.str) {
+					visitType(field.Type())
+				}
+			}
+			for _, con := range nt.cons {
+				for _, param := range tupleVars(con.Type().(*types.Signature).Params()) {
+					visitType(param.Type())
+				}
+			}
+		}
+	}
+	visitType = func(typ types.Type) {
+		switch typ := typ.(type) {
+		case *types.Slice:
+			visitType(typ.Elem())
+		case *types.Pointer:
+			visitType(typ.Elem())
+		case *types.TypeParam:
+			visit(typ.Obj().Name())
+		}
+	}
+	visit(defName)
+	return res
+}
+
+// unptr returns the type parameter that typ points to, if any, and
+// the number of pointer indirections.
+func unptr(typ types.Type) (*types.TypeParam, int) {
+	ptrs := 0
+	for {
+		ptr, ok := typ.(*types.Pointer)
+		if !ok {
+			break
+		}
+		typ = ptr.Elem()
+		ptrs++
+	}
+	tparam, _ := typ.(*types.TypeParam)
+	return tparam, ptrs
+}
+
+// unembed reports whether typ, an embedded element of a
+// non-terminal's interface, is of the form "*X | omit", which removes
+// the embedding of X. If so, it also returns X's name.
+func unembed(typ types.Type) (string, bool) {
+	union, ok := typ.(*types.Union)
+	if !ok || union.Len() != 2 {
+		return "", false
+	}
+	var embed *types.TypeParam
+	ptrs, omitted := 0, false
+	for i := 0; i < union.Len(); i++ {
+		switch t := union.Term(i).Type().(type) {
+		case *types.Named:
+			omitted = omitted || t.Obj().Name() == "omit"
+		default:
+			embed, ptrs = unptr(t)
+		}
+	}
+	if embed == nil || ptrs != 1 || !omitted {
+		return "", false
+	}
+	return embed.Obj().Name(), true
+}
+
+// ifaceCommand reports whether iface, a type parameter's constraint,
+// embeds a command: define or redefine, which declares a terminal with
+// a custom representation or a primitive table, or meta, which
+// declares a metadata field with an annotation type. If so, it returns
+// the command.
+func ifaceCommand(iface *types.Interface) (string, bool) {
+	if iface.IsImplicit() {
+		return "", false
+	}
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		if typ := iface.EmbeddedType(i); isKeyword(typ, "define", "redefine", "meta") {
+			return typ.(*types.Named).Obj().Name(), true
+		}
+	}
+	return "", false
+}
+
+// declTerm returns the terminal declared by tparam, whose constraint
+// is define or redefine, or an interface embedding one of them. If
+// it's a redefinition, old is the previous declaration, whose
+// representation and primitives are kept unless changed.
+//
+// Besides the command, the interface may embed a representation type
+// R, as in "interface{ define; R }", or else declare a primitive
+// table; see declPrims.
+func (L lang) declTerm(tparam *types.TypeParam, old *term) *term {
+	t := &term{pass: L.name, pos: tparam.Obj().Pos()}
+	if old != nil {
+		t.repr, t.prims = old.repr, old.prims
+	}
+	iface, ok := tparam.Constraint().(*types.Interface)
+	if !ok {
+		return t
+	}
+
+	var repr types.Type
+	var rest []types.Type
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		switch typ := iface.EmbeddedType(i); {
+		case isKeyword(typ, "define", "redefine"):
+		case isKeyword(typ, primKeywords...), isPtr(typ), isOmit(typ):
+			rest = append(rest, typ)
+		case repr != nil:
+			errorf(t.pos, "%v has more than one representation: %v and %v", tparam.Obj().Name(), repr, typ)
+		default:
+			repr = typ
+		}
+	}
+
+	if repr != nil {
+		if len(rest) != 0 || iface.NumMethods() != 0 {
+			errorf(t.pos, "%v cannot have both a representation and primitives", tparam.Obj().Name())
+		}
+		checkRepr(t.pos, tparam.Obj().Name(), repr)
+		t.repr, t.prims = repr, nil
+		return t
+	}
+	if len(rest) != 0 || iface.NumMethods() != 0 {
+		if t.repr != nil {
+			errorf(t.pos, "%v cannot have both a representation and primitives", tparam.Obj().Name())
+		}
+		t.prims = L.declPrims(tparam.Obj().Name(), t.pos, t.prims, rest, iface)
+	}
+	return t
+}
+
+// isKeyword reports whether typ is one of the named keyword types.
+func isKeyword(typ types.Type, names ...string) bool {
+	named, ok := typ.(*types.Named)
+	return ok && slices.Contains(names, named.Obj().Name())
+}
+
+// isPtr reports whether typ is a pointer to a type parameter.
+func isPtr(typ types.Type) bool {
+	tparam, ptrs := unptr(typ)
+	return tparam != nil && ptrs == 1
+}
+
+// isOmit reports whether typ is a union including omit.
+func isOmit(typ types.Type) bool {
+	union, ok := typ.(*types.Union)
+	if !ok {
+		return false
+	}
+	for i := 0; i < union.Len(); i++ {
+		if isKeyword(union.Term(i).Type(), "omit") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRepr reports an error if repr, the representation of the named
+// terminal, is unsupported. Its underlying type must be an integer or
+// string type, and if it has a String method, then its pointer must
+// have an UnmarshalText method that reads the result back.
+func checkRepr(pos token.Pos, termName string, repr types.Type) {
+	basic, ok := repr.Underlying().(*types.Basic)
+	if _, isParam := repr.(*types.TypeParam); isParam || !ok || basic.Info()&(types.IsInteger|types.IsString) == 0 {
+		errorf(pos, "%v has unsupported representation %v; want an integer or string type", termName, repr)
+		return
+	}
+	if hasMethod(repr, "String", stringSig) && !hasMethod(types.NewPointer(repr), "UnmarshalText", unmarshalTextSig) {
+		errorf(pos, "%v is represented by %v, which has a String method but no UnmarshalText method", termName, repr)
+	}
+}
+
+var (
+	errorType        = types.Universe.Lookup("error").Type()
+	stringSig        = types.NewSignatureType(nil, nil, nil, nil, types.NewTuple(types.NewParam(token.NoPos, nil, "", types.Typ[types.String])), false)
+	validSig         = types.NewSignatureType(nil, nil, nil, nil, types.NewTuple(types.NewParam(token.NoPos, nil, "", types.Typ[types.Bool])), false)
+	unmarshalTextSig = types.NewSignatureType(nil, nil, nil, types.NewTuple(types.NewParam(token.NoPos, nil, "", types.NewSlice(types.Typ[types.Byte]))), types.NewTuple(types.NewParam(token.NoPos, nil, "", errorType)), false)
+)
+
+// hasMethod reports whether typ has an exported method with the given
+// name and signature.
+func hasMethod(typ types.Type, name string, sig *types.Signature) bool {
+	obj, _, _ := types.LookupFieldOrMethod(typ, false, nil, name)
+	fn, ok := obj.(*types.Func)
+	return ok && types.Identical(fn.Type(), sig)
+}
+
+// checkField reports an error if field, a field of a production or
+// product type, has a type that mklang doesn't support: anything
+// besides terminals, non-terminals, integers, and slices and
+// pointers thereof.
+func checkField(field *types.Var) {
+	typ := field.Type()
+	for {
+		switch t := typ.(type) {
+		case *types.Slice:
+			typ = t.Elem()
+			continue
+		case *types.Pointer:
+			typ = t.Elem()
+			continue
+		case *types.TypeParam:
+			return
+		case *types.Basic:
+			if t.Info()&types.IsInteger != 0 {
+				return
+			}
+		}
+		errorf(field.Pos(), "field %v has unsupported type %v", field.Name(), field.Type())
+		return
+	}
+}
+
+// checkRefs reports an error if typ, the type of field, refers to a
+// terminal or non-terminal that is not defined in L.
+func (L lang) checkRefs(field *types.Var, typ types.Type) {
+	switch typ := typ.(type) {
+	case *types.Slice:
+		L.checkRefs(field, typ.Elem())
+	case *types.Pointer:
+		L.checkRefs(field, typ.Elem())
+	case *types.TypeParam:
+		if defName := typ.Obj().Name(); L.defs[defName] == nil {
+			errorf(field.Pos(), "field %v refers to %v, which is not defined in %v", field.Name(), defName, L.name)
+		}
+	}
+}
+
 func keys[K cmp.Ordered, V any](m map[K]V) []K {
 	res := make([]K, 0, len(m))
 	for k := range m {
@@ -300,14 +1005,103 @@ func keys[K cmp.Ordered, V any](m map[K]V) []K {
 	return res
 }
 
+// A node is a Go type generated for a language whose values can
+// appear within a syntax tree: a terminal, a product type, or a
+// production of a non-terminal.
+type node struct {
+	name    string
+	owner   string // defining terminal or non-terminal
+	pos     token.Pos
+	fields  []*types.Var // nil for terminals
+	term    bool
+	product bool
+}
+
+// nodes returns the nodes of L, in the order they're declared by
+// L.String.
+func (L lang) nodes() []node {
+	var res []node
+	for _, defName := range keys(L.defs) {
+		switch def := L.defs[defName].(type) {
+		case *term:
+			res = append(res, node{name: defName, owner: defName, pos: def.pos, term: true})
+		case *nonterm:
+			if def.str != nil {
+				res = append(res, node{name: defName, owner: defName, pos: def.pos, fields: structFields(def.str), product: true})
+				continue
+			}
+			for _, conName := range keys(def.cons) {
+				con := def.cons[conName]
+				res = append(res, node{name: conName, owner: defName, pos: con.Pos(), fields: tupleVars(con.Type().(*types.Signature).Params())})
+			}
+		}
+	}
+	return res
+}
+
+// members returns the nodes of L whose values are also values of
+// the named non-terminal: its own productions, plus the productions
+// and terminals that it embeds.
+func (L lang) members(defName string) []node {
+	var res []node
+	for _, n := range L.nodes() {
+		var isAlso map[string]bool
+		switch def := L.defs[n.owner].(type) {
+		case *term:
+			isAlso = def.isAlso
+		case *nonterm:
+			isAlso = def.isAlso
+		}
+		if !n.product && (n.owner == defName || isAlso[defName]) {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+func structFields(str *types.Struct) []*types.Var {
+	res := make([]*types.Var, str.NumFields())
+	for i := range res {
+		res[i] = str.Field(i)
+	}
+	return res
+}
+
+func tupleVars(tup *types.Tuple) []*types.Var {
+	res := make([]*types.Var, tup.Len())
+	for i := range res {
+		res[i] = tup.At(i)
+	}
+	return res
+}
+
 func (L lang) String() string {
 	var head, body, foot strings.Builder
 
 	fmt.Fprintf(&head, "// Code generated by Hermes. DO NOT EDIT.\n\n")
 
-	fmt.Fprintf(&head, "package %v\n", L.name)
+	fmt.Fprintf(&head, "package %v\n\n", L.pkg)
+
+	imports := make(map[string]bool)
+	methods, usesStrconv := L.reprMethods(imports)
+	prims, primsUseStrconv := L.primMethods(imports)
+	var std []string
+	if usesStrconv || primsUseStrconv {
+		std = append(std, "strconv")
+	}
+	var metaField string
+	if L.meta != nil {
+		metaField = L.meta.name + " " + L.metaType(imports)
+	}
+	head.WriteString(importDecl(std, imports))
 
 	fmt.Fprintf(&head, "type terminal int\n\n")
+	if L.entry != "" {
+		fmt.Fprintf(&head, "// Entry is the entry non-terminal of %v.\n", L.name)
+		fmt.Fprintf(&head, "type Entry = %v\n\n", L.entry)
+	}
+	fmt.Fprintf(&head, "// A Node is a terminal, product type, or production value.\n")
+	fmt.Fprintf(&head, "type Node interface{ isNode() }\n\n")
 	fmt.Fprintf(&head, "type (\n")
 
 	is := func(typs []string, cons ...string) {
@@ -323,18 +1117,28 @@ func (L lang) String() string {
 		default:
 			panic("unknown def")
 		case *term:
-			fmt.Fprintf(&head, "\n\t%v terminal // from %v", defName, def.pass)
-			is(keys(def.isAlso), defName)
+			typ := "terminal"
+			if def.repr != nil {
+				typ = qualify(def.repr, imports)
+			}
+			fmt.Fprintf(&head, "\n\t%v %v // from %v", defName, typ, def.pass)
+			is(append([]string{"Node"}, keys(def.isAlso)...), defName)
 		case *nonterm:
 			if def.str != nil {
-				if len(def.cons) != 0 && len(def.embeds) != 0 {
-					fmt.Printf("%v is both a set and product type\n", defName)
+				str := def.str.String()
+				if metaField != "" {
+					sep := "; "
+					if def.str.NumFields() == 0 {
+						sep = ""
+					}
+					str = strings.TrimSuffix(str, "}") + sep + metaField + "}"
 				}
-				fmt.Fprintf(&head, "\n\t%v %v", defName, def.str)
+				fmt.Fprintf(&head, "\n\t%v %v", defName, str)
+				is([]string{"Node"}, defName)
 				continue
 			}
 
-			fmt.Fprintf(&body, "\n\ntype (\n\t%v interface{", defName)
+			fmt.Fprintf(&body, "\n\ntype (\n\t%v interface{Node; ", defName)
 			for _, key := range keys(def.isAlso) {
 				if key != defName {
 					fmt.Fprintf(&body, "%v; ", key)
@@ -344,13 +1148,7 @@ func (L lang) String() string {
 			fmt.Fprintf(&body, "}")
 
 			for _, conName := range keys(def.cons) {
-				con := def.cons[conName]
-				if res := con.Results(); res.Len() != 0 {
-					if res.Len() != 1 || res.At(0).Type().(*types.Named).Obj().Name() != "omit" {
-						fmt.Printf("expected omit: %v\n", res)
-					}
-					continue
-				}
+				con := def.cons[conName].Type().(*types.Signature)
 				fmt.Fprintf(&body, "\n\t%v struct{", conName)
 				var prev types.Type
 				for i := 0; i < con.Params().Len(); i++ {
@@ -366,13 +1164,16 @@ func (L lang) String() string {
 					prev = param.Type()
 				}
 				if prev != nil {
-					fmt.Fprintf(&body, " %v ", prev)
+					fmt.Fprintf(&body, " %v;", prev)
+				}
+				if metaField != "" {
+					fmt.Fprintf(&body, " %v;", metaField)
 				}
 				fmt.Fprintf(&body, "}")
 			}
 			fmt.Fprintf(&body, "\n)")
 
-			is(keys(def.isAlso), keys(def.cons)...)
+			is(append([]string{"Node"}, keys(def.isAlso)...), keys(def.cons)...)
 		}
 	}
 
@@ -388,6 +1189,9 @@ func (L lang) String() string {
 		head.WriteString(foot.String())
 	}
 
+	head.WriteString(methods)
+	head.WriteString(prims)
+
 	return head.String()
 }
 
@@ -398,5 +1202,23 @@ const (
 	inherit  keyword = "inherit"
 	define   keyword = "define"
 	redefine keyword = "redefine"
+	entry    keyword = "entry"
 	language keyword = "language"
+
+	// Primitive classes and flags.
+	value     keyword = "value"
+	effect    keyword = "effect"
+	predicate keyword = "predicate"
+	pure      keyword = "pure"
+	alloc     keyword = "alloc"
+
+	// Binding roles of fields.
+	bind  keyword = "bind"
+	scope keyword = "scope"
+
+	// Declares a metadata field.
+	meta keyword = "meta"
+
+	// Declares the language that a language extends.
+	extends keyword = "extends"
 )
@@ -0,0 +1,301 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// prelude declares the keywords used by language declarations.
+const prelude = `package lang
+
+type omit any
+type inherit any
+type define any
+type redefine any
+type entry any
+type language any
+
+type value any
+type effect any
+type predicate any
+type pure any
+type alloc any
+
+type bind[T any] any
+type scope[T any] any
+
+type meta any
+
+type extends any
+`
+
+// load type-checks src, a file of language declarations in package
+// lang that may use the keywords declared by prelude, and resets the
+// diagnostics.
+func load(t *testing.T, src string) *packages.Package {
+	t.Helper()
+	return loadFiles(t, map[string]string{"prelude.go": prelude, "lang.go": src})
+}
+
+// loadFiles type-checks the named files as package lang, and resets
+// the diagnostics.
+func loadFiles(t *testing.T, srcs map[string]string) *packages.Package {
+	t.Helper()
+	fset = token.NewFileSet()
+	diagnostics = nil
+	var files []*ast.File
+	for _, name := range keys(srcs) {
+		file, err := parser.ParseFile(fset, name, srcs[name], parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("lang", fset, files, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &packages.Package{
+		Name:    "lang",
+		PkgPath: "lang",
+		Fset:    fset,
+		Syntax:  files,
+		Types:   pkg,
+	}
+}
+
+// loadExample loads the example language declarations, and resets
+// the diagnostics. It returns the absolute path of their directory.
+func loadExample(t *testing.T) (string, *packages.Package) {
+	t.Helper()
+	example, err := filepath.Abs(filepath.Join("..", "..", "example"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset = token.NewFileSet()
+	diagnostics = nil
+	cfg := packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedModule,
+		Fset: fset,
+		Dir:  example,
+	}
+	pkgs, err := packages.Load(&cfg, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 || len(pkgs) != 1 {
+		t.Fatalf("loading %v failed", example)
+	}
+	return example, pkgs[0]
+}
+
+// reported returns the reported diagnostics, sorted by position, as
+// "line:col: message" or, for diagnostics without a position, just
+// the message.
+func reported() []string {
+	sortDiagnostics()
+	var res []string
+	for _, d := range diagnostics {
+		msg := d.msg
+		if d.warning {
+			msg = "warning: " + msg
+		}
+		if d.pos.IsValid() {
+			msg = fmt.Sprintf("%d:%d: %v", d.pos.Line, d.pos.Column, msg)
+		}
+		res = append(res, msg)
+	}
+	return res
+}
+
+func TestLanguages(t *testing.T) {
+	pkg := load(t, `package lang
+
+// Lexer is generic, but it isn't a language.
+type Lexer[T any] struct{ toks []T }
+
+type Token[T any] interface{ ~int }
+
+type L0[Expr interface{ entry; Nop() }] language
+type L1[Expr interface{ Skip() }] language
+`)
+	var got []string
+	for _, l := range languages(pkg) {
+		got = append(got, l.Obj().Name())
+	}
+	if want := []string{"L0", "L1"}; !slices.Equal(got, want) {
+		t.Errorf("languages = %v, want %v", got, want)
+	}
+	if got := reported(); len(got) != 0 {
+		t.Errorf("got diagnostics %q, want none", got)
+	}
+}
+
+func TestMarker(t *testing.T) {
+	defer func(marker string) { *markerFlag = marker }(*markerFlag)
+
+	tests := []struct {
+		marker string
+		src    string
+		want   []string
+	}{
+		{
+			marker: "hermes",
+			src: `package lang
+
+type hermes any
+
+type L0[Expr interface{ entry; Nop() }] hermes
+type L1[Expr interface{ Skip() }] language
+`,
+		},
+		{
+			marker: "hermes",
+			src: `package lang
+
+type L0[Expr interface{ entry; Nop() }] language
+`,
+			want: []string{"marker hermes is not a type declared in lang"},
+		},
+		{
+			marker: "language",
+			src: `package lang
+
+type L0 language
+type L1[Expr interface{ entry; Nop() }] language
+`,
+			want: []string{"3:6: L0 is defined as language, but isn't generic; a language's definitions are its type parameters"},
+		},
+	}
+	for _, tt := range tests {
+		*markerFlag = tt.marker
+		languages(load(t, tt.src))
+		if got := reported(); !slices.Equal(got, tt.want) {
+			t.Errorf("-marker %v: got diagnostics %q, want %q", tt.marker, got, tt.want)
+		}
+	}
+}
+
+func TestPkgName(t *testing.T) {
+	defer func(format string) { *pkgNameFlag = format }(*pkgNameFlag)
+	const src = `package lang
+
+type L0[Expr interface{ entry; Nop() }] language
+`
+
+	*pkgNameFlag = "lang%s"
+	_, files := build(load(t, src), "out")
+	if len(files) == 0 {
+		t.Fatalf("generated no files; diagnostics %q", reported())
+	}
+	for _, f := range files {
+		if dir := filepath.Join("out", "langL0"); filepath.Dir(f.path) != dir {
+			t.Errorf("generated %v, want it in %v", f.path, dir)
+		}
+		if !strings.Contains(string(f.src), "\npackage langL0\n") {
+			t.Errorf("%v doesn't declare package langL0", f.path)
+		}
+	}
+
+	*pkgNameFlag = "%s-lang"
+	if _, files := build(load(t, src), "out"); len(files) != 0 {
+		t.Errorf("generated %d files for an invalid package name", len(files))
+	}
+	if got, want := reported(), []string{`3:6: invalid package name "L0-lang" for L0`}; !slices.Equal(got, want) {
+		t.Errorf("got diagnostics %q, want %q", got, want)
+	}
+}
+
+func TestResolveParents(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		order []string
+		want  []string
+	}{
+		{
+			name: "implicit",
+			src: `package lang
+
+type A[Expr interface{ entry; Nop() }] language
+type B[Expr interface{ Skip() }] language
+type C[Expr interface{ Halt() }] language
+`,
+			order: []string{"A", "B", "C"},
+		},
+		{
+			name: "explicit",
+			src: `package lang
+
+type A[Expr interface{ entry; Nop() }] language
+type B[Expr interface{ Skip() }] language
+type C[A extends, Expr interface{ Halt() }] language
+`,
+			order: []string{"A", "B", "C"},
+		},
+		{
+			name: "cycle",
+			src: `package lang
+
+type A[B extends, Expr interface{ entry; Nop() }] language
+type B[Expr interface{ Skip() }] language
+type C[Expr interface{ Halt() }] language
+`,
+			// The cycle is reported once, and C, which extends B, is
+			// dropped along with it.
+			want: []string{"3:6: language cycle: A extends B extends A"},
+		},
+		{
+			name: "missing parent",
+			src: `package lang
+
+type A[Expr interface{ entry; Nop() }] language
+type B[Z extends, Expr interface{ Skip() }] language
+type C[Expr interface{ Halt() }] language
+`,
+			order: []string{"A"},
+			want:  []string{"4:8: B extends Z, which is not a language"},
+		},
+		{
+			name: "extends twice",
+			src: `package lang
+
+type A[Expr interface{ entry; Nop() }] language
+type B[Expr interface{ Skip() }] language
+type C[A extends, B extends, Expr interface{ Halt() }] language
+`,
+			order: []string{"A", "B", "C"},
+			want:  []string{"5:19: C already extends A"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			langs, _ := resolveParents(languages(load(t, tt.src)))
+			var order []string
+			for _, l := range langs {
+				order = append(order, l.Obj().Name())
+			}
+			if !slices.Equal(order, tt.order) {
+				t.Errorf("resolveParents ordered %v, want %v", order, tt.order)
+			}
+			if got := reported(); !slices.Equal(got, tt.want) {
+				t.Errorf("got diagnostics:\n\t%v\nwant:\n\t%v", strings.Join(got, "\n\t"), strings.Join(tt.want, "\n\t"))
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// parse returns the source for L's Parse functions, which read
+// values written in the notation produced by Unparse. A Parse
+// function is generated for each non-terminal and product type.
+//
+// List heads are compared after folding with sexpr.Fold, so that
+// nanopass spellings like "set!" are accepted too.
+func (L lang) parse() string {
+	var b strings.Builder
+
+	imports := map[string]bool{sexprPath: true}
+	parsers := L.reprParsers(imports)
+	if L.meta != nil {
+		parsers += fmt.Sprintf(`// parseMeta returns the metadata of a node parsed from x, which
+// records its span.
+func parseMeta(x sexpr.Expr) %[1]v {
+	return %[1]v{Span: lang.SpanOf(x)}
+}
+
+`, L.metaType(imports))
+	}
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	b.WriteString(importDecl([]string{"strconv"}, imports))
+
+	for _, defName := range keys(L.defs) {
+		if _, ok := L.defs[defName].(*nonterm); ok {
+			fmt.Fprintf(&b, "// Parse%v parses an s-expression from src and returns the\n// corresponding %v value.\n", defName, defName)
+			fmt.Fprintf(&b, "func Parse%v(src string) (%v, error) { return parse(src, parse%v) }\n\n", defName, defName, defName)
+		}
+	}
+
+	for _, defName := range keys(L.defs) {
+		nt, ok := L.defs[defName].(*nonterm)
+		if !ok {
+			continue
+		}
+
+		fmt.Fprintf(&b, "func parse%v(x sexpr.Expr) %v {\n", defName, defName)
+
+		if nt.str != nil {
+			n := node{name: defName, fields: structFields(nt.str), product: true}
+			fmt.Fprintf(&b, "%vreturn %v\n}\n\n", L.parseArgs(n), L.parseFields(n))
+			continue
+		}
+
+		if atoms := L.atoms(defName); len(atoms) != 0 {
+			fmt.Fprintf(&b, "if _, ok := x.(*sexpr.Atom); ok {\n")
+			for _, n := range atoms {
+				var read string
+				if n.term {
+					read = L.parseField("x", nil, n.name)
+				} else {
+					fields := []string{fmt.Sprintf("%v: %v", n.fields[0].Name(), L.parseField("x", n.fields[0].Type(), ""))}
+					if L.meta != nil {
+						fields = append(fields, fmt.Sprintf("%v: parseMeta(x)", L.meta.name))
+					}
+					read = fmt.Sprintf("%v{%v}", n.name, strings.Join(fields, ", "))
+				}
+				fmt.Fprintf(&b, "if v, ok := tryParse(x, func(x sexpr.Expr) %v { return %v }); ok {\nreturn v\n}\n", defName, read)
+			}
+			fmt.Fprintf(&b, "panic(sexpr.Errorf(x, \"expected %%v, found %%v\", %q, x))\n}\n", defName)
+		}
+		if app, ok := L.application(defName); ok && L.implicit(app) {
+			fmt.Fprintf(&b, "if isApplication(x) {\n")
+			fmt.Fprintf(&b, "args := arity(x, %q, list(x), 2, true)\n", app.name)
+			fmt.Fprintf(&b, "return %v\n}\n", L.parseFields(app))
+		}
+
+		fmt.Fprintf(&b, "switch h := form(x, %q); h {\n", defName)
+		for _, n := range L.members(defName) {
+			fmt.Fprintf(&b, "case %q:\n", head(n.name))
+			if n.term {
+				fmt.Fprintf(&b, "args := arity(x, %q, list(x)[1:], 1, false)\n", n.name)
+				fmt.Fprintf(&b, "return %v\n", L.parseField("args[0]", nil, n.name))
+				continue
+			}
+			fmt.Fprintf(&b, "%vreturn %v\n", L.parseArgs(n), L.parseFields(n))
+		}
+		fmt.Fprintf(&b, "default:\npanic(unexpected(x, h, %q))\n}\n}\n\n", defName)
+	}
+
+	b.WriteString(parsers)
+
+	fmt.Fprintf(&b, "// forms maps the heads of productions and terminals to their names.\n")
+	fmt.Fprintf(&b, "var forms = map[string]string{\n")
+	for _, n := range L.nodes() {
+		if !n.product {
+			fmt.Fprintf(&b, "%q: %q,\n", head(n.name), n.name)
+		}
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	fmt.Fprintf(&b, "// omitted maps the heads of productions and terminals that were\n")
+	fmt.Fprintf(&b, "// removed by an earlier language to their names and that language.\n")
+	fmt.Fprintf(&b, "var omitted = map[string][2]string{\n")
+	for _, name := range keys(L.gone) {
+		fmt.Fprintf(&b, "%q: {%q, %q},\n", head(name), name, L.gone[name])
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	fmt.Fprintf(&b, `func unexpected(x sexpr.Expr, h, want string) *sexpr.Error {
+	if name, ok := forms[h]; ok {
+		return sexpr.Errorf(x, "%%v does not belong to %%v in %v", name, want)
+	}
+	if name, ok := omitted[h]; ok {
+		return sexpr.Errorf(x, "%%v was omitted in %%v", name[0], name[1])
+	}
+	return sexpr.Errorf(x, "unknown form %%q in %v", h)
+}
+`, L.name, L.name)
+
+	fmt.Fprintf(&b, `
+// tryParse returns the result of f, and whether it succeeded.
+func tryParse[T any](x sexpr.Expr, f func(sexpr.Expr) T) (res T, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, isErr := r.(*sexpr.Error); !isErr {
+				panic(r)
+			}
+		}
+	}()
+	return f(x), true
+}
+
+func parse[T any](src string, f func(sexpr.Expr) T) (res T, err error) {
+	x, err := sexpr.Parse(src)
+	if err != nil {
+		return res, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*sexpr.Error)
+			if !ok {
+				panic(r)
+			}
+			err = e
+		}
+	}()
+	return f(x), nil
+}
+
+// form returns the folded head of x, which must be a list headed by
+// an atom.
+func form(x sexpr.Expr, want string) string {
+	if l, ok := x.(*sexpr.List); ok && len(l.Elems) != 0 {
+		if h, ok := l.Elems[0].(*sexpr.Atom); ok {
+			return sexpr.Fold(h.Text)
+		}
+	}
+	panic(sexpr.Errorf(x, "expected %%v, found %%v", want, x))
+}
+
+// list returns the elements of x, which must be a list.
+func list(x sexpr.Expr) []sexpr.Expr {
+	if l, ok := x.(*sexpr.List); ok {
+		return l.Elems
+	}
+	panic(sexpr.Errorf(x, "expected list, found %%v", x))
+}
+
+// arity returns args, the arguments of x, after checking that there
+// are n of them, or at least n-1 if variadic.
+func arity(x sexpr.Expr, name string, args []sexpr.Expr, n int, variadic bool) []sexpr.Expr {
+	switch {
+	case variadic && len(args) < n-1:
+		panic(sexpr.Errorf(x, "%%v expects at least %%d fields, found %%d", name, n-1, len(args)))
+	case !variadic && len(args) != n:
+		panic(sexpr.Errorf(x, "%%v expects %%d fields, found %%d", name, n, len(args)))
+	}
+	return args
+}
+
+func parseAll[T any](xs []sexpr.Expr, f func(sexpr.Expr) T) []T {
+	if len(xs) == 0 {
+		return nil
+	}
+	res := make([]T, len(xs))
+	for i, x := range xs {
+		res[i] = f(x)
+	}
+	return res
+}
+
+func parseOpt[T any](x sexpr.Expr, f func(sexpr.Expr) T) *T {
+	if a, ok := x.(*sexpr.Atom); ok && a.Text == "#f" {
+		return nil
+	}
+	res := f(x)
+	return &res
+}
+
+func parseInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](x sexpr.Expr, want string) T {
+	if a, ok := x.(*sexpr.Atom); ok {
+		if v, err := strconv.ParseInt(a.Text, 0, 64); err == nil && int64(T(v)) == v {
+			return T(v)
+		}
+	}
+	panic(sexpr.Errorf(x, "expected %%v, found %%v", want, x))
+}
+`)
+
+	if L.hasApplications() {
+		fmt.Fprintf(&b, `
+// isApplication reports whether x is an application written without
+// its head, which is the case if its first element isn't one.
+func isApplication(x sexpr.Expr) bool {
+	l, ok := x.(*sexpr.List)
+	return ok && len(l.Elems) != 0 && !isHead(l.Elems[0])
+}
+
+// isHead reports whether x is the head of a form, including those of
+// other non-terminals and of forms omitted by an earlier language.
+func isHead(x sexpr.Expr) bool {
+	a, ok := x.(*sexpr.Atom)
+	if !ok {
+		return false
+	}
+	h := sexpr.Fold(a.Text)
+	_, isForm := forms[h]
+	_, isOmitted := omitted[h]
+	return isForm || isOmitted
+}
+`)
+	}
+
+	return b.String()
+}
+
+// parseArgs returns the statement that extracts the arguments of x
+// for node n, if n has any fields.
+func (L lang) parseArgs(n node) string {
+	args := "list(x)"
+	if !n.product {
+		args += "[1:]"
+	}
+	variadic := false
+	if len(n.fields) != 0 {
+		_, variadic = n.fields[len(n.fields)-1].Type().(*types.Slice)
+	}
+	stmt := fmt.Sprintf("arity(x, %q, %v, %d, %v)\n", n.name, args, len(n.fields), variadic)
+	if len(n.fields) == 0 {
+		return stmt
+	}
+	return "args := " + stmt
+}
+
+// parseFields returns a composite literal of type n, which reads its
+// fields from args, and its metadata, if any, from x.
+func (L lang) parseFields(n node) string {
+	var fields []string
+	for i, field := range n.fields {
+		x := fmt.Sprintf("args[%d]", i)
+		typ := field.Type()
+		if slice, ok := typ.(*types.Slice); ok && i == len(n.fields)-1 {
+			x = "args"
+			if i > 0 {
+				x = fmt.Sprintf("args[%d:]", i)
+			}
+			fields = append(fields, fmt.Sprintf("%v: parseAll(%v, %v)", field.Name(), x, L.parseFunc(slice.Elem())))
+			continue
+		}
+		fields = append(fields, fmt.Sprintf("%v: %v", field.Name(), L.parseField(x, typ, "")))
+	}
+	if L.meta != nil {
+		fields = append(fields, fmt.Sprintf("%v: parseMeta(x)", L.meta.name))
+	}
+	return fmt.Sprintf("%v{%v}", n.name, strings.Join(fields, ", "))
+}
+
+// parseField returns an expression that reads a value of type typ
+// from x, an expression of type sexpr.Expr. If termName is non-empty,
+// it names the terminal to read instead.
+func (L lang) parseField(x string, typ types.Type, termName string) string {
+	if termName != "" {
+		if L.defs[termName].(*term).custom() {
+			return fmt.Sprintf("parse%v(%v)", termName, x)
+		}
+		return fmt.Sprintf("parseInt[%v](%v, %q)", termName, x, termName)
+	}
+
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		defName := typ.Obj().Name()
+		switch L.defs[defName].(type) {
+		case *term:
+			return L.parseField(x, nil, defName)
+		case *nonterm:
+			return fmt.Sprintf("parse%v(%v)", defName, x)
+		}
+	case *types.Slice:
+		return fmt.Sprintf("parseAll(list(%v), %v)", x, L.parseFunc(typ.Elem()))
+	case *types.Pointer:
+		return fmt.Sprintf("parseOpt(%v, %v)", x, L.parseFunc(typ.Elem()))
+	case *types.Basic:
+		if typ.Info()&types.IsInteger != 0 {
+			return fmt.Sprintf("parseInt[%v](%v, %q)", typ, x, typ)
+		}
+	}
+	panic(fmt.Sprintf("unexpected field type %v", typ)) // see checkField
+}
+
+// parseFunc returns a function that reads values of type typ.
+func (L lang) parseFunc(typ types.Type) string {
+	if typ, ok := typ.(*types.TypeParam); ok {
+		switch def := L.defs[typ.Obj().Name()].(type) {
+		case *term:
+			if def.custom() {
+				return "parse" + typ.Obj().Name()
+			}
+		case *nonterm:
+			return "parse" + typ.Obj().Name()
+		}
+	}
+	return fmt.Sprintf("func(x sexpr.Expr) %v { return %v }", typ, L.parseField("x", typ, ""))
+}
@@ -0,0 +1,277 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// A primitive table is a terminal whose values are an enumeration of
+// primitive operations, declared like a non-terminal's productions:
+//
+//	Primitive interface {
+//		define
+//		Car(p any) (value, pure)
+//		Cons(car, cdr any) (value, pure, alloc)
+//		VectorSet(v, i, x any) effect
+//	}
+//
+// The parameters give a primitive's arity, and the results give its
+// class (value, effect, or predicate) and flags: pure if it's free of
+// side effects, and alloc if it allocates memory. A primitive is
+// written in s-expressions as its name in lowercase, with hyphens
+// between words, like "vector-set".
+//
+// A table can also be derived from another one: embedding "*X" copies
+// X's primitives, restricted to any embedded classes, and "K | omit",
+// where K is a class or flag, removes the primitives that have it. As
+// with productions, "Name() omit" removes a single primitive.
+//
+// Each primitive has the same value in every language, so values can
+// be converted between tables, such as when splitting one by class.
+
+// primKeywords are the classes and flags of primitives.
+var primKeywords = []string{"value", "effect", "predicate", "pure", "alloc"}
+
+// A prim is a primitive of a primitive table.
+type prim struct {
+	fn       *types.Func // declaring method
+	code     int
+	arity    int
+	variadic bool
+	class    string // value, effect, or predicate
+	pure     bool
+	alloc    bool
+}
+
+// text returns how p is written in s-expressions.
+func (p *prim) text() string { return metavar(p.fn.Name()) }
+
+// has reports whether p's class or flags include k.
+func (p *prim) has(k string) bool {
+	switch k {
+	case "pure":
+		return p.pure
+	case "alloc":
+		return p.alloc
+	}
+	return p.class == k
+}
+
+// declPrims returns the primitives of the table named defName, as
+// declared by iface, whose embedded elements besides its command are
+// rest. The table's previous primitives, if it's being redefined, are
+// old.
+func (L lang) declPrims(defName string, pos token.Pos, old map[string]*prim, rest []types.Type, iface *types.Interface) map[string]*prim {
+	prims := make(map[string]*prim, len(old))
+	for name, p := range old {
+		prims[name] = p
+	}
+
+	var classes, omits []string
+	var srcs []string
+	for _, typ := range rest {
+		switch {
+		case isKeyword(typ, primKeywords...):
+			k := typ.(*types.Named).Obj().Name()
+			if k == "pure" || k == "alloc" {
+				errorf(pos, "%v embeds flag %v; only classes restrict the primitives of an embedded table", defName, k)
+				continue
+			}
+			classes = append(classes, k)
+		case isPtr(typ):
+			tparam, _ := unptr(typ)
+			srcs = append(srcs, tparam.Obj().Name())
+		default:
+			k, ok := unomit(typ)
+			if !ok {
+				errorf(pos, "%v embeds %v, which is not a primitive table, class, or flag", defName, typ)
+				continue
+			}
+			omits = append(omits, k)
+		}
+	}
+	if len(classes) != 0 && len(srcs) == 0 {
+		errorf(pos, "%v embeds classes %v, but no primitive table to restrict", defName, strings.Join(classes, ", "))
+	}
+
+	for _, src := range srcs {
+		t, ok := L.defs[src].(*term)
+		if !ok || t.prims == nil {
+			errorf(pos, "%v embeds %v, which is not a primitive table", defName, src)
+			continue
+		}
+		for name, p := range t.prims {
+			if len(classes) == 0 || slices.Contains(classes, p.class) {
+				prims[name] = p
+			}
+		}
+	}
+
+	for _, k := range omits {
+		n := len(prims)
+		for name, p := range prims {
+			if p.has(k) {
+				delete(prims, name)
+			}
+		}
+		if len(prims) == n {
+			warnf(pos, "redundant omit: %v has no %v primitives", defName, k)
+		}
+	}
+
+	// Assign new primitives their values in declaration order.
+	methods := make([]*types.Func, iface.NumMethods())
+	for i := range methods {
+		methods[i] = iface.Method(i)
+	}
+	slices.SortFunc(methods, func(a, b *types.Func) int { return cmp.Compare(a.Pos(), b.Pos()) })
+
+	for _, fn := range methods {
+		sig := fn.Type().(*types.Signature)
+		if res := sig.Results(); res.Len() == 1 && isKeyword(res.At(0).Type(), "omit") {
+			if prims[fn.Name()] == nil {
+				warnf(fn.Pos(), "redundant omit: %v has no primitive %v", defName, fn.Name())
+			}
+			delete(prims, fn.Name())
+			continue
+		}
+
+		p := &prim{fn: fn, arity: sig.Params().Len(), variadic: sig.Variadic()}
+		if p.variadic {
+			p.arity--
+		}
+		for i := 0; i < sig.Results().Len(); i++ {
+			typ := sig.Results().At(i).Type()
+			switch {
+			case isKeyword(typ, "pure"):
+				p.pure = true
+			case isKeyword(typ, "alloc"):
+				p.alloc = true
+			case isKeyword(typ, "value", "effect", "predicate") && p.class == "":
+				p.class = typ.(*types.Named).Obj().Name()
+			default:
+				errorf(fn.Pos(), "unexpected result for primitive %v: %v", fn.Name(), typ)
+			}
+		}
+		if p.class == "" {
+			errorf(fn.Pos(), "primitive %v needs a class: value, effect, or predicate", fn.Name())
+		}
+
+		if _, ok := L.codes[fn.Name()]; !ok {
+			L.codes[fn.Name()] = len(L.codes) + 1
+		}
+		p.code = L.codes[fn.Name()]
+		prims[fn.Name()] = p
+	}
+
+	return prims
+}
+
+// unomit reports whether typ is of the form "K | omit", where K is a
+// primitive class or flag. If so, it also returns K.
+func unomit(typ types.Type) (string, bool) {
+	union, ok := typ.(*types.Union)
+	if !ok || union.Len() != 2 {
+		return "", false
+	}
+	var k string
+	omitted := false
+	for i := 0; i < union.Len(); i++ {
+		switch t := union.Term(i).Type(); {
+		case isKeyword(t, "omit"):
+			omitted = true
+		case isKeyword(t, primKeywords...):
+			k = t.(*types.Named).Obj().Name()
+		}
+	}
+	return k, omitted && k != ""
+}
+
+// sortedPrims returns t's primitives, sorted by value.
+func (t *term) sortedPrims() []*prim {
+	var res []*prim
+	for _, p := range t.prims {
+		res = append(res, p)
+	}
+	slices.SortFunc(res, func(a, b *prim) int { return cmp.Compare(a.code, b.code) })
+	return res
+}
+
+// primSig returns the declaration of p, as written in the language
+// declarations.
+func primSig(p *prim) string {
+	sig := types.TypeString(p.fn.Type(), func(*types.Package) string { return "" })
+	return p.fn.Name() + strings.TrimPrefix(sig, "func")
+}
+
+// primTable returns the name of the generated variable that maps the
+// values of the named primitive table to their descriptions.
+func primTable(defName string) string {
+	return strings.ToLower(defName[:1]) + defName[1:] + "Table"
+}
+
+// primMethods returns the source for the constants, methods, and
+// lookup functions of L's primitive tables. It adds the import paths
+// they need to imports, and reports whether they use strconv.
+func (L lang) primMethods(imports map[string]bool) (string, bool) {
+	var b strings.Builder
+	usesStrconv := false
+	for _, defName := range keys(L.defs) {
+		t, ok := L.defs[defName].(*term)
+		if !ok || t.prims == nil {
+			continue
+		}
+		imports[langPath] = true
+		usesStrconv = true
+		prims := t.sortedPrims()
+		table := primTable(defName)
+
+		if len(prims) != 0 {
+			fmt.Fprintf(&b, "\n\n// The primitives of %v.\nconst (\n", defName)
+			for _, p := range prims {
+				fmt.Fprintf(&b, "%v%v %v = %d // %v\n", defName, p.fn.Name(), defName, p.code, p.text())
+			}
+			fmt.Fprintf(&b, ")")
+		}
+
+		fmt.Fprintf(&b, "\n\n// Prim returns the description of x, or nil if x is not one of\n// %v's primitives.\n", defName)
+		fmt.Fprintf(&b, "func (x %v) Prim() *lang.Prim { return %v[x] }", defName, table)
+
+		fmt.Fprintf(&b, "\n\n// String returns the name of x, as written by Unparse.\n")
+		fmt.Fprintf(&b, "func (x %v) String() string {\nif p := x.Prim(); p != nil {\nreturn p.Name\n}\n", defName)
+		fmt.Fprintf(&b, "return %q + strconv.Itoa(int(x)) + \")\"\n}", defName+"(")
+
+		fmt.Fprintf(&b, "\n\n// Valid reports whether x is one of %v's primitives.\n", defName)
+		fmt.Fprintf(&b, "func (x %v) Valid() bool { return x.Prim() != nil }", defName)
+
+		fmt.Fprintf(&b, "\n\n// Lookup%v returns the primitive of %v with the given\n// name, if any.\n", defName, defName)
+		fmt.Fprintf(&b, "func Lookup%v(name string) (%v, bool) {\n", defName, defName)
+		fmt.Fprintf(&b, "for x, p := range %v {\nif p.Name == name {\nreturn x, true\n}\n}\nreturn 0, false\n}", table)
+
+		fmt.Fprintf(&b, "\n\nvar %v = map[%v]*lang.Prim{\n", table, defName)
+		for _, p := range prims {
+			fmt.Fprintf(&b, "%v%v: {Name: %q, Code: %d, Arity: %d", defName, p.fn.Name(), p.text(), p.code, p.arity)
+			if p.variadic {
+				fmt.Fprintf(&b, ", Variadic: true")
+			}
+			fmt.Fprintf(&b, ", Class: lang.%v", strings.ToUpper(p.class[:1])+p.class[1:])
+			if p.pure {
+				fmt.Fprintf(&b, ", Pure: true")
+			}
+			if p.alloc {
+				fmt.Fprintf(&b, ", Alloc: true")
+			}
+			fmt.Fprintf(&b, "},\n")
+		}
+		fmt.Fprintf(&b, "}")
+	}
+	return b.String(), usesStrconv
+}
@@ -0,0 +1,214 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const primSrc = `package lang
+
+type L0[
+	Primitive interface {
+		define
+		Car(p any) (value, pure)
+		Cons(car, cdr any) (value, pure, alloc)
+		VectorSet(v, i, x any) effect
+		IsPair(x any) (predicate, pure)
+		List(xs ...any) (value, alloc)
+	},
+	ValuePrim interface {
+		define
+		*Primitive
+		value
+	},
+	NoAlloc interface {
+		define
+		*Primitive
+		alloc | omit
+	},
+	Expr interface {
+		entry
+		Call(P Primitive, V ValuePrim, N NoAlloc, Args []Expr)
+	},
+] language
+
+type L1[
+	Primitive interface {
+		redefine
+		Car() omit
+		Void() (value, pure)
+	},
+] language
+`
+
+// primCodes returns the names of the primitives of the table named
+// defName in L, with their values.
+func primCodes(L lang, defName string) map[string]int {
+	codes := make(map[string]int)
+	for _, p := range L.defs[defName].(*term).sortedPrims() {
+		codes[p.fn.Name()] = p.code
+	}
+	return codes
+}
+
+func TestPrims(t *testing.T) {
+	chain, files := build(load(t, primSrc), "out")
+	if got := reported(); len(got) != 0 {
+		t.Fatalf("unexpected diagnostics:\n%v", got)
+	}
+
+	tests := []struct {
+		L       lang
+		defName string
+		want    map[string]int
+	}{
+		{chain[0], "Primitive", map[string]int{"Car": 1, "Cons": 2, "VectorSet": 3, "IsPair": 4, "List": 5}},
+
+		// Restricting a table by class, or omitting a flag.
+		{chain[0], "ValuePrim", map[string]int{"Car": 1, "Cons": 2, "List": 5}},
+		{chain[0], "NoAlloc", map[string]int{"Car": 1, "VectorSet": 3, "IsPair": 4}},
+
+		// Redefining a table keeps the values of its remaining
+		// primitives, and doesn't reuse those of omitted ones.
+		{chain[1], "Primitive", map[string]int{"Cons": 2, "VectorSet": 3, "IsPair": 4, "List": 5, "Void": 6}},
+	}
+	for _, tt := range tests {
+		got := primCodes(tt.L, tt.defName)
+		if len(got) != len(tt.want) {
+			t.Errorf("%v.%v has primitives %v, want %v", tt.L.name, tt.defName, got, tt.want)
+			continue
+		}
+		for name, code := range tt.want {
+			if got[name] != code {
+				t.Errorf("%v.%v has primitives %v, want %v", tt.L.name, tt.defName, got, tt.want)
+				break
+			}
+		}
+	}
+
+	p := chain[0].defs["Primitive"].(*term).prims["List"]
+	if p.arity != 0 || !p.variadic || p.class != "value" || p.pure || !p.alloc {
+		t.Errorf("List has arity %v, variadic %v, class %v, pure %v, alloc %v; want 0, true, value, false, true", p.arity, p.variadic, p.class, p.pure, p.alloc)
+	}
+
+	src := make(map[string]string)
+	for _, f := range files {
+		src[f.path] = string(f.src)
+	}
+	for _, want := range []string{
+		"PrimitiveVectorSet Primitive = 3 // vector-set",
+		"func (x Primitive) Prim() *lang.Prim { return primitiveTable[x] }",
+		"func LookupPrimitive(name string) (Primitive, bool) {\n\tfor x, p := range primitiveTable {\n\t\tif p.Name == name {",
+		`PrimitiveList:      {Name: "list", Code: 5, Arity: 0, Variadic: true, Class: lang.Value, Alloc: true},`,
+		"func LookupNoAlloc(name string) (NoAlloc, bool) {",
+	} {
+		if !strings.Contains(src["out/L0/L0.go"], want) {
+			t.Errorf("out/L0/L0.go does not contain %q", want)
+		}
+	}
+	if strings.Contains(src["out/L1/L1.go"], "PrimitiveCar") {
+		t.Errorf("out/L1/L1.go declares omitted primitive Car")
+	}
+}
+
+func TestPrimDiagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "redundant omit",
+			src: primSrc + `
+type L2[
+	Primitive interface {
+		redefine
+		Car() omit
+	},
+	ValuePrim interface {
+		redefine
+		effect | omit
+	},
+] language
+`,
+			want: []string{
+				"39:3: warning: redundant omit: Primitive has no primitive Car",
+				"41:2: warning: redundant omit: ValuePrim has no effect primitives",
+			},
+		},
+		{
+			name: "class",
+			src: `package lang
+
+type L0[
+	Primitive interface {
+		define
+		Car(p any) pure
+		Cdr(p any) (value, other)
+	},
+	Expr interface {
+		entry
+		Call(P Primitive)
+	},
+] language
+
+type other any
+`,
+			want: []string{
+				"6:3: primitive Car needs a class: value, effect, or predicate",
+				"7:3: unexpected result for primitive Cdr: lang.other",
+			},
+		},
+		{
+			name: "embeddings",
+			src: `package lang
+
+type L0[
+	Symbol interface{ define; string },
+	Primitive interface {
+		define
+		Car(p any) (value, pure)
+	},
+	Pure interface {
+		define
+		*Primitive
+		pure
+	},
+	Value interface {
+		define
+		value
+		Car(p any) (value, pure)
+	},
+	Sym interface {
+		define
+		*Symbol
+		Car(p any) (value, pure)
+	},
+	Expr interface {
+		entry
+		*Symbol
+		Call(P Primitive, Q Pure, V Value, S Sym)
+	},
+] language
+`,
+			want: []string{
+				"9:2: Pure embeds flag pure; only classes restrict the primitives of an embedded table",
+				"14:2: Value embeds classes value, but no primitive table to restrict",
+				"19:2: Sym embeds Symbol, which is not a primitive table",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build(load(t, tt.src), "out")
+			if got := reported(); !slices.Equal(got, tt.want) {
+				t.Errorf("got diagnostics:\n\t%v\nwant:\n\t%v", strings.Join(got, "\n\t"), strings.Join(tt.want, "\n\t"))
+			}
+		})
+	}
+}
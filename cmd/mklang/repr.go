@@ -0,0 +1,146 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// Terminals declared as "interface{ define; R }" are represented by
+// the Go type R instead of int. Their generated types get String and
+// Valid methods, which defer to R's own methods when it has them, and
+// their generated Parse code reads the text written by String.
+
+// custom reports whether t is read by a generated parse function,
+// rather than as a plain integer: that is, whether it has a custom
+// representation or is a primitive table.
+func (t *term) custom() bool {
+	return t.repr != nil || t.prims != nil
+}
+
+// stringer reports whether t's representation has its own String
+// method, in which case it's read back with UnmarshalText.
+func (t *term) stringer() bool {
+	return t.repr != nil && hasMethod(t.repr, "String", stringSig)
+}
+
+// text reports whether t is written as arbitrary text, rather than as
+// an integer.
+func (t *term) text() bool {
+	if t.prims != nil {
+		return true
+	}
+	if t.repr == nil {
+		return false
+	}
+	return t.stringer() || t.repr.Underlying().(*types.Basic).Info()&types.IsString != 0
+}
+
+// qualify returns the Go syntax for typ within a generated package,
+// adding the import paths it needs to imports.
+func qualify(typ types.Type, imports map[string]bool) string {
+	return types.TypeString(typ, func(pkg *types.Package) string {
+		imports[pkg.Path()] = true
+		return pkg.Name()
+	})
+}
+
+// importDecl returns an import declaration for the standard packages
+// std, followed by the other packages in imports.
+func importDecl(std []string, imports map[string]bool) string {
+	if len(std)+len(imports) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "import (\n")
+	for _, path := range std {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	if len(std) != 0 && len(imports) != 0 {
+		fmt.Fprintf(&b, "\n")
+	}
+	for _, path := range keys(imports) {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	fmt.Fprintf(&b, ")\n\n")
+	return b.String()
+}
+
+// reprMethods returns the source for the String and Valid methods of
+// L's terminals that have custom representations. It adds the import
+// paths they need to imports, and reports whether they use strconv.
+func (L lang) reprMethods(imports map[string]bool) (string, bool) {
+	var b strings.Builder
+	usesStrconv := false
+	for _, defName := range keys(L.defs) {
+		t, ok := L.defs[defName].(*term)
+		if !ok || t.repr == nil {
+			continue
+		}
+		repr := qualify(t.repr, imports)
+
+		fmt.Fprintf(&b, "\n\n// String returns the text of x, as written by Unparse.\n")
+		fmt.Fprintf(&b, "func (x %v) String() string { ", defName)
+		basic := t.repr.Underlying().(*types.Basic)
+		switch {
+		case t.stringer():
+			fmt.Fprintf(&b, "return %v(x).String()", repr)
+		case basic.Info()&types.IsString != 0:
+			fmt.Fprintf(&b, "return string(x)")
+		case basic.Info()&types.IsUnsigned != 0:
+			fmt.Fprintf(&b, "return strconv.FormatUint(uint64(x), 10)")
+			usesStrconv = true
+		default:
+			fmt.Fprintf(&b, "return strconv.FormatInt(int64(x), 10)")
+			usesStrconv = true
+		}
+		fmt.Fprintf(&b, " }")
+
+		fmt.Fprintf(&b, "\n\n// Valid reports whether x is a valid %v.\n", defName)
+		fmt.Fprintf(&b, "func (x %v) Valid() bool { ", defName)
+		if hasMethod(t.repr, "Valid", validSig) {
+			fmt.Fprintf(&b, "return %v(x).Valid()", repr)
+		} else {
+			fmt.Fprintf(&b, "return true")
+		}
+		fmt.Fprintf(&b, " }")
+	}
+	return b.String(), usesStrconv
+}
+
+// reprParsers returns the source for the functions that read L's
+// terminals that have custom representations or are primitive tables,
+// adding the import paths they need to imports. Values that aren't
+// Valid are rejected.
+func (L lang) reprParsers(imports map[string]bool) string {
+	var b strings.Builder
+	for _, defName := range keys(L.defs) {
+		t, ok := L.defs[defName].(*term)
+		if !ok || !t.custom() {
+			continue
+		}
+
+		fmt.Fprintf(&b, "func parse%v(x sexpr.Expr) %v {\n", defName, defName)
+		switch {
+		case t.prims != nil:
+			fmt.Fprintf(&b, "if a, ok := x.(*sexpr.Atom); ok {\nif v, ok := Lookup%v(a.Text); ok {\nreturn v\n}\n}\n", defName)
+			fmt.Fprintf(&b, "panic(sexpr.Errorf(x, \"expected %%v, found %%v\", %q, x))\n", defName)
+		case t.stringer():
+			fmt.Fprintf(&b, "if a, ok := x.(*sexpr.Atom); ok {\nvar v %v\n", qualify(t.repr, imports))
+			fmt.Fprintf(&b, "if err := v.UnmarshalText([]byte(a.Text)); err == nil && %v(v).Valid() {\nreturn %v(v)\n}\n}\n", defName, defName)
+			fmt.Fprintf(&b, "panic(sexpr.Errorf(x, \"expected %%v, found %%v\", %q, x))\n", defName)
+		case t.text():
+			fmt.Fprintf(&b, "if a, ok := x.(*sexpr.Atom); ok {\nif v := %v(a.Text); v.Valid() {\nreturn v\n}\n}\n", defName)
+			fmt.Fprintf(&b, "panic(sexpr.Errorf(x, \"expected %%v, found %%v\", %q, x))\n", defName)
+		default:
+			fmt.Fprintf(&b, "if v := parseInt[%v](x, %q); v.Valid() {\nreturn v\n}\n", defName, defName)
+			fmt.Fprintf(&b, "panic(sexpr.Errorf(x, \"invalid %%v %%v\", %q, x))\n", defName)
+		}
+		fmt.Fprintf(&b, "}\n\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepr(t *testing.T) {
+	_, files := build(load(t, `package lang
+
+type Name string
+
+type Reg uint8
+
+func (r Reg) Valid() bool { return r < 16 }
+
+type Op int
+
+func (o Op) String() string { return "op" }
+func (o *Op) UnmarshalText(b []byte) error { return nil }
+
+type L0[
+	Symbol interface{ define; Name },
+	Register interface{ define; Reg },
+	Oper interface{ define; Op },
+	Expr interface {
+		entry
+		*Symbol
+		Load(R Register, O Oper)
+	},
+] language
+`), "out")
+	if got := reported(); len(got) != 0 {
+		t.Fatalf("unexpected diagnostics:\n%v", got)
+	}
+
+	src := make(map[string]string)
+	for _, f := range files {
+		src[f.path] = string(f.src)
+	}
+	tests := []struct {
+		path, want string
+	}{
+		// A string representation is written as is.
+		{"out/L0/L0.go", "func (x Symbol) String() string { return string(x) }"},
+
+		// An integer representation is written in decimal, and
+		// validated by its own Valid method.
+		{"out/L0/L0.go", "func (x Register) String() string { return strconv.FormatUint(uint64(x), 10) }"},
+		{"out/L0/L0.go", "func (x Register) Valid() bool { return lang.Reg(x).Valid() }"},
+		{"out/L0/parse.go", `parseInt[Register](x, "Register")`},
+
+		// A fmt.Stringer is written by its String method, and read
+		// back by its UnmarshalText method.
+		{"out/L0/L0.go", "func (x Oper) String() string { return lang.Op(x).String() }"},
+		{"out/L0/parse.go", "if err := v.UnmarshalText([]byte(a.Text)); err == nil && Oper(v).Valid() {"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(src[tt.path], tt.want) {
+			t.Errorf("%v does not contain %q", tt.path, tt.want)
+		}
+	}
+}
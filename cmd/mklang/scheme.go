@@ -0,0 +1,336 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// printScheme prints each language in chain as a nanopass
+// define-language form. If extends is set, each language after the
+// first is written as an extension of the one it extends.
+func printScheme(w io.Writer, chain []lang, extends bool) {
+	for i, L := range chain {
+		if i > 0 {
+			fmt.Fprintf(w, "\n")
+		}
+		if extends && L.parent != "" {
+			fmt.Fprint(w, L.schemeDelta(parentOf(chain, L)))
+		} else {
+			fmt.Fprint(w, L.scheme())
+		}
+	}
+}
+
+// scheme returns L as a define-language form.
+//
+// Each terminal and non-terminal has a single metavariable, which is
+// its name in lowercase, with hyphens between words. Product types
+// are written inline, in square brackets, and Go integer types become
+// terminals, as named by intTerms.
+func (L lang) scheme() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "(define-language %v", L.name)
+	if L.entry != "" {
+		fmt.Fprintf(&b, "\n  (entry %v)", L.entry)
+	}
+	fmt.Fprintf(&b, "\n  (terminals")
+	for _, t := range L.schemeTerms() {
+		fmt.Fprintf(&b, "\n    (%v (%v))", t, t)
+	}
+	fmt.Fprintf(&b, ")")
+	for _, ntName := range L.schemeNonterms() {
+		fmt.Fprintf(&b, "\n  (%v (%v)", ntName, metavar(ntName))
+		for _, pat := range L.patterns(ntName) {
+			fmt.Fprintf(&b, "\n    %v", pat)
+		}
+		fmt.Fprintf(&b, ")")
+	}
+	fmt.Fprintf(&b, ")\n")
+	return b.String()
+}
+
+// schemeDelta returns L as a define-language form that extends L0.
+func (L lang) schemeDelta(L0 lang) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "(define-language %v\n  (extends %v)", L.name, L0.name)
+	if L.entry != L0.entry {
+		fmt.Fprintf(&b, "\n  (entry %v)", L.entry)
+	}
+
+	// A terminal that was redefined is removed and added back.
+	terms0, terms := L0.schemeTerms(), L.schemeTerms()
+	var minus, plus []string
+	for _, t := range terms0 {
+		if !slices.Contains(terms, t) || L.redefined(L0, t) {
+			minus = append(minus, t)
+		}
+	}
+	for _, t := range terms {
+		if !slices.Contains(terms0, t) || L.redefined(L0, t) {
+			plus = append(plus, t)
+		}
+	}
+	if len(minus)+len(plus) != 0 {
+		fmt.Fprintf(&b, "\n  (terminals")
+		for _, ext := range []struct {
+			op    string
+			terms []string
+		}{{"-", minus}, {"+", plus}} {
+			if len(ext.terms) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "\n    (%v", ext.op)
+			for i, t := range ext.terms {
+				if i > 0 {
+					fmt.Fprintf(&b, "\n       ")
+				} else {
+					fmt.Fprintf(&b, " ")
+				}
+				fmt.Fprintf(&b, "(%v (%v))", t, t)
+			}
+			fmt.Fprintf(&b, ")")
+		}
+		fmt.Fprintf(&b, ")")
+	}
+
+	ntNames := L.schemeNonterms()
+	for _, ntName := range L0.schemeNonterms() {
+		if !slices.Contains(ntNames, ntName) {
+			ntNames = append(ntNames, ntName)
+		}
+	}
+	for _, ntName := range ntNames {
+		pats0, pats := L0.patterns(ntName), L.patterns(ntName)
+		var minus, plus []string
+		for _, pat := range pats0 {
+			if !slices.Contains(pats, pat) {
+				minus = append(minus, pat)
+			}
+		}
+		for _, pat := range pats {
+			if !slices.Contains(pats0, pat) {
+				plus = append(plus, pat)
+			}
+		}
+		if len(minus)+len(plus) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  (%v (%v)", ntName, metavar(ntName))
+		for _, ext := range []struct {
+			op   string
+			pats []string
+		}{{"-", minus}, {"+", plus}} {
+			if len(ext.pats) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "\n    (%v", ext.op)
+			for i, pat := range ext.pats {
+				if i > 0 {
+					fmt.Fprintf(&b, "\n       ")
+				} else {
+					fmt.Fprintf(&b, " ")
+				}
+				fmt.Fprintf(&b, "%v", pat)
+			}
+			fmt.Fprintf(&b, ")")
+		}
+		fmt.Fprintf(&b, ")")
+	}
+
+	fmt.Fprintf(&b, ")\n")
+	return b.String()
+}
+
+// redefined reports whether the terminal named by the metavariable t
+// was redefined since L0.
+func (L lang) redefined(L0 lang, t string) bool {
+	for defName, def := range L.defs {
+		if def, ok := def.(*term); ok && metavar(defName) == t {
+			def0, ok := L0.defs[defName].(*term)
+			return ok && def0.pass != def.pass
+		}
+	}
+	return false
+}
+
+// schemeTerms returns the sorted names of L's terminals, including
+// the Go integer types used by its fields.
+func (L lang) schemeTerms() []string {
+	set := make(map[string]bool)
+	for defName, def := range L.defs {
+		if _, ok := def.(*term); ok {
+			set[metavar(defName)] = true
+		}
+	}
+	for _, n := range L.nodes() {
+		for _, field := range n.fields {
+			typ := field.Type()
+			for {
+				if elem, ok := typ.(interface{ Elem() types.Type }); ok {
+					typ = elem.Elem()
+					continue
+				}
+				break
+			}
+			if basic, ok := typ.(*types.Basic); ok {
+				set[intTerms[basic.Kind()]] = true
+			}
+		}
+	}
+	return keys(set)
+}
+
+// schemeNonterms returns the names of L's sum non-terminals, with
+// the entry first.
+func (L lang) schemeNonterms() []string {
+	var res []string
+	for _, defName := range keys(L.defs) {
+		if nt, ok := L.defs[defName].(*nonterm); ok && nt.str == nil {
+			res = append(res, defName)
+		}
+	}
+	if i := slices.Index(res, L.entry); i > 0 {
+		res = append(append([]string{L.entry}, res[:i]...), res[i+1:]...)
+	}
+	return res
+}
+
+// patterns returns the productions of the named non-terminal, as
+// nanopass patterns.
+func (L lang) patterns(ntName string) []string {
+	nt, ok := L.defs[ntName].(*nonterm)
+	if !ok || nt.str != nil {
+		return nil
+	}
+	var res []string
+	for _, embed := range keys(nt.embeds) {
+		res = append(res, metavar(embed))
+	}
+	for _, conName := range keys(nt.cons) {
+		fields := tupleVars(nt.cons[conName].Type().(*types.Signature).Params())
+
+		// Metavariables that appear more than once are numbered.
+		counts := make(map[string]int)
+		L.countMetas(counts, fields)
+		p := pattern{L: L, counts: counts, next: make(map[string]int)}
+		res = append(res, "("+strings.Join(append([]string{head(conName)}, p.fields(fields, "")...), " ")+")")
+	}
+	return res
+}
+
+// countMetas counts the references to each metavariable in fields.
+func (L lang) countMetas(counts map[string]int, fields []*types.Var) {
+	var visit func(typ types.Type)
+	visit = func(typ types.Type) {
+		switch typ := typ.(type) {
+		case *types.TypeParam:
+			if nt, ok := L.defs[typ.Obj().Name()].(*nonterm); ok && nt.str != nil {
+				L.countMetas(counts, structFields(nt.str))
+				return
+			}
+			counts[metavar(typ.Obj().Name())]++
+		case *types.Slice:
+			visit(typ.Elem())
+		case *types.Pointer:
+			visit(typ.Elem())
+		case *types.Basic:
+			counts[intTerms[typ.Kind()]]++
+		}
+	}
+	for _, field := range fields {
+		visit(field.Type())
+	}
+}
+
+// A pattern renders the fields of a production as a nanopass
+// pattern.
+type pattern struct {
+	L      lang
+	counts map[string]int // total references to each metavariable
+	next   map[string]int // next number for each metavariable
+}
+
+// fields returns the patterns for fields, where stars is the suffix
+// for metavariables within repeated elements. As in the s-expression
+// notation, a slice in the last field is spliced.
+func (p *pattern) fields(fields []*types.Var, stars string) []string {
+	var res []string
+	for i, field := range fields {
+		if slice, ok := field.Type().(*types.Slice); ok && i == len(fields)-1 {
+			res = append(res, p.typ(slice.Elem(), stars+"*"), "...")
+			continue
+		}
+		res = append(res, p.typ(field.Type(), stars))
+	}
+	return res
+}
+
+// typ returns the pattern for a field of type typ.
+func (p *pattern) typ(typ types.Type, stars string) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		if nt, ok := p.L.defs[typ.Obj().Name()].(*nonterm); ok && nt.str != nil {
+			return "[" + strings.Join(p.fields(structFields(nt.str), stars), " ") + "]"
+		}
+		return p.metavar(metavar(typ.Obj().Name()), stars)
+	case *types.Slice:
+		return "(" + p.typ(typ.Elem(), stars+"*") + " ...)"
+	case *types.Pointer:
+		return "(maybe " + p.typ(typ.Elem(), stars) + ")"
+	case *types.Basic:
+		return p.metavar(intTerms[typ.Kind()], stars)
+	}
+	panic(fmt.Sprintf("unexpected field type %v", typ)) // see checkField
+}
+
+// meta returns a reference to the metavariable m.
+func (p *pattern) metavar(m, stars string) string {
+	if p.counts[m] > 1 {
+		n := p.next[m]
+		p.next[m]++
+		return fmt.Sprintf("%v%d%v", m, n, stars)
+	}
+	return m + stars
+}
+
+// intTerms names the terminals for Go integer types. The names are
+// spelled out, since nanopass reads a metavariable's trailing digits
+// as a reference number, and avoid "int", the head of productions like
+// Const's Int.
+var intTerms = map[types.BasicKind]string{
+	types.Int:     "fixnum",
+	types.Int8:    "int-eight",
+	types.Int16:   "int-sixteen",
+	types.Int32:   "int-thirty-two",
+	types.Int64:   "integer",
+	types.Uint:    "unsigned",
+	types.Uint8:   "unsigned-eight",
+	types.Uint16:  "unsigned-sixteen",
+	types.Uint32:  "unsigned-thirty-two",
+	types.Uint64:  "unsigned-integer",
+	types.Uintptr: "uintptr",
+}
+
+// metavar returns the metavariable for the named definition: its name
+// in lowercase, with hyphens between words.
+func metavar(defName string) string {
+	var b strings.Builder
+	for i, r := range defName {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
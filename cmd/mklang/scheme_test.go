@@ -0,0 +1,56 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSchemeRoundTrip checks that the example languages, printed as
+// define-language forms and converted back by nanoimport, are printed
+// the same way again, without any warnings, even with -strict.
+func TestSchemeRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	defer func(strict bool) { *strictFlag = strict }(*strictFlag)
+	*strictFlag = true
+
+	_, pkg := loadExample(t)
+	chain, _ := build(pkg, "out")
+	if got := reported(); len(got) != 0 {
+		t.Fatalf("unexpected diagnostics:\n%v", strings.Join(got, "\n"))
+	}
+	var want strings.Builder
+	printScheme(&want, chain, true)
+
+	dir := t.TempDir()
+	ss, goFile := filepath.Join(dir, "lang.ss"), filepath.Join(dir, "lang.go")
+	if err := os.WriteFile(ss, []byte(want.String()), 0666); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "run", "../nanoimport", "-o", goFile, ss)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("nanoimport failed: %v\n%s", err, out)
+	}
+	src, err := os.ReadFile(goFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain, _ = build(loadFiles(t, map[string]string{"lang.go": string(src)}), "out")
+	if got := reported(); len(got) != 0 {
+		t.Fatalf("unexpected diagnostics for imported languages:\n%v", strings.Join(got, "\n"))
+	}
+	var got strings.Builder
+	printScheme(&got, chain, true)
+	if got.String() != want.String() {
+		t.Errorf("round trip changed the languages:\n%v", unified("before", "after", want.String(), got.String()))
+	}
+}
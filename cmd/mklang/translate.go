@@ -0,0 +1,192 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// A language that extends another gets a Translator, which does the
+// part of a pass that the pass doesn't spell out: each node that the
+// pass leaves alone is translated 1:1 into the node of the same name,
+// with its children translated in turn. If both languages have the
+// same metadata field, the translation carries it along, so a node's
+// source span survives every pass that doesn't rewrite it.
+
+// translate returns the source for L's Translator, which translates
+// syntax trees of P, the language that L extends.
+func (L lang) translate(P lang) string {
+	var b, cases strings.Builder
+
+	carry := L.meta != nil && L.meta.String() == P.meta.String()
+	mine := make(map[string]node)
+	for _, n := range L.nodes() {
+		mine[n.name] = n
+	}
+
+	for _, n0 := range P.nodes() {
+		n, ok := mine[n0.name]
+		if !ok || n.term != n0.term || n.product != n0.product {
+			continue
+		}
+		if n.term {
+			t0, t := P.defs[n0.name].(*term), L.defs[n.name].(*term)
+			switch {
+			case t.prims != nil && t0.prims != nil:
+				fmt.Fprintf(&cases, "case %v.%v:\nif y := %v(n); y.Valid() {\nreturn y\n}\n", P.pkg, n0.name, n.name)
+			case t.prims == nil && t0.prims == nil && (t.repr == nil) == (t0.repr == nil) && (t.repr == nil || types.Identical(t.repr, t0.repr)):
+				fmt.Fprintf(&cases, "case %v.%v:\nreturn %v(n)\n", P.pkg, n0.name, n.name)
+			}
+			continue
+		}
+		if !oneToOne(n0.fields, n.fields) {
+			continue
+		}
+		var elems []string
+		for _, field := range n.fields {
+			elems = append(elems, fmt.Sprintf("%v: %v", field.Name(), L.translateExpr(P, "n."+field.Name(), field.Type())))
+		}
+		if carry {
+			elems = append(elems, fmt.Sprintf("%[1]v: n.%[1]v", L.meta.name))
+		}
+		fmt.Fprintf(&cases, "case %v.%v:\nreturn %v{%v}\n", P.pkg, n0.name, n.name, strings.Join(elems, ", "))
+	}
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+	fmt.Fprintf(&b, "import (\n\"fmt\"\n\"reflect\"\n\n%q\n)\n\n", P.path)
+
+	fmt.Fprintf(&b, `// A Translator translates syntax trees of %[1]v into %[2]v.
+type Translator struct {
+	// Pass, if non-nil, translates the nodes that the pass rewrites,
+	// reporting false for those it leaves alone. It can call
+	// TranslateAs to translate their children.
+	Pass func(t *Translator, x %[1]v.Node) (Node, bool)
+}
+
+// Translate returns the translation of x by t.Pass, if it handles x,
+// or else the node of %[2]v with the same name as x, whose children are
+// the translations of x's children, and whose metadata, if any, is
+// x's. It panics if %[2]v has no such node.
+func (t *Translator) Translate(x %[1]v.Node) Node {
+	if x == nil {
+		return nil
+	}
+	if t.Pass != nil {
+		if y, ok := t.Pass(t, x); ok {
+			return y
+		}
+	}
+	switch n := x.(type) {
+%[3]v}
+	panic(fmt.Sprintf("%%T has no 1:1 translation into %[2]v", x))
+}
+
+// TranslateAs returns the translation of x by t, as a T. It panics if
+// the translation isn't a T.
+func TranslateAs[T Node](t *Translator, x %[1]v.Node) T {
+	y := t.Translate(x)
+	if y == nil {
+		var zero T
+		return zero
+	}
+	res, ok := y.(T)
+	if !ok {
+		panic(fmt.Sprintf("cannot use %%T, the translation of %%T, as %%v", y, x, reflect.TypeFor[T]()))
+	}
+	return res
+}
+
+func translateSlice[S, T any](xs []S, f func(S) T) []T {
+	if xs == nil {
+		return nil
+	}
+	res := make([]T, len(xs))
+	for i, x := range xs {
+		res[i] = f(x)
+	}
+	return res
+}
+
+func translatePtr[S, T any](p *S, f func(S) T) *T {
+	if p == nil {
+		return nil
+	}
+	x := f(*p)
+	return &x
+}
+`, P.pkg, L.name, cases.String())
+
+	return b.String()
+}
+
+// oneToOne reports whether fields0, the fields of a node of the
+// language that another extends, correspond 1:1 to fields, those of
+// the node of the same name in the other: they have the same names, and types that
+// refer to the same definitions.
+func oneToOne(fields0, fields []*types.Var) bool {
+	if len(fields0) != len(fields) {
+		return false
+	}
+	for i, field := range fields {
+		if fields0[i].Name() != field.Name() || !sameShape(fields0[i].Type(), field.Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameShape reports whether typ0 and typ, the types of fields of two
+// languages, refer to definitions of the same names in the same way.
+func sameShape(typ0, typ types.Type) bool {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		tparam, ok := typ0.(*types.TypeParam)
+		return ok && tparam.Obj().Name() == typ.Obj().Name()
+	case *types.Slice:
+		slice, ok := typ0.(*types.Slice)
+		return ok && sameShape(slice.Elem(), typ.Elem())
+	case *types.Pointer:
+		ptr, ok := typ0.(*types.Pointer)
+		return ok && sameShape(ptr.Elem(), typ.Elem())
+	}
+	return types.Identical(typ0, typ)
+}
+
+// translateExpr returns an expression for the translation of x, an
+// expression of the type in P that corresponds to typ.
+func (L lang) translateExpr(P lang, x string, typ types.Type) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		return fmt.Sprintf("TranslateAs[%v](t, %v)", typ.Obj().Name(), x)
+	case *types.Slice:
+		return fmt.Sprintf("translateSlice(%v, %v)", x, L.translator(P, typ.Elem()))
+	case *types.Pointer:
+		return fmt.Sprintf("translatePtr(%v, %v)", x, L.translator(P, typ.Elem()))
+	}
+	return x
+}
+
+// translator returns a function that translates values of the type in
+// P that corresponds to typ.
+func (L lang) translator(P lang, typ types.Type) string {
+	return fmt.Sprintf("func(x %v) %v { return %v }", qualifyParams(P.pkg, typ), types.TypeString(typ, nil), L.translateExpr(P, "x", typ))
+}
+
+// qualifyParams returns the syntax of typ, with the definitions it
+// refers to qualified by pkg.
+func qualifyParams(pkg string, typ types.Type) string {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		return pkg + "." + typ.Obj().Name()
+	case *types.Slice:
+		return "[]" + qualifyParams(pkg, typ.Elem())
+	case *types.Pointer:
+		return "*" + qualifyParams(pkg, typ.Elem())
+	}
+	return types.TypeString(typ, nil)
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslate(t *testing.T) {
+	_, files := build(load(t, `package lang
+
+type L0[
+	M meta,
+	Symbol interface{ define; string },
+	Expr interface {
+		entry
+		*Symbol
+		Ref(Base Expr, Index *Expr)
+		Call(Fun Expr, Args [][]Expr)
+		Let(Bindings []Binding, Body Expr)
+	},
+	Binding struct {
+		Var Symbol
+		Val Expr
+	},
+] language
+
+type L1[
+	Expr, Symbol inherit,
+	Binding struct {
+		Var Symbol
+		Val *Expr
+	},
+] language
+
+type L2[
+	M omit,
+] language
+`), "out")
+	if got := reported(); len(got) != 0 {
+		t.Fatalf("unexpected diagnostics:\n%v", got)
+	}
+
+	src := make(map[string]string)
+	for _, f := range files {
+		src[f.path] = string(f.src)
+	}
+	if _, ok := src["out/L0/translate.go"]; ok {
+		t.Errorf("generated a Translator for L0, which extends no language")
+	}
+	tests := []struct {
+		path, want string
+		ok         bool
+	}{
+		{"out/L1/translate.go", `"lang/out/L0"`, true},
+		{"out/L1/translate.go", "func (t *Translator) Translate(x L0.Node) Node {", true},
+		{"out/L1/translate.go", "return Ref{Base: TranslateAs[Expr](t, n.Base), Index: translatePtr(n.Index, func(x L0.Expr) Expr { return TranslateAs[Expr](t, x) }), M: n.M}", true},
+		{"out/L1/translate.go", "Args: translateSlice(n.Args, func(x []L0.Expr) []Expr {", true},
+		{"out/L1/translate.go", "return Symbol(n)", true},
+
+		// Binding changed, so a pass has to translate it.
+		{"out/L1/translate.go", "case L0.Binding:", false},
+		{"out/L1/translate.go", "case L0.Let:", true},
+
+		// L2 has no metadata to carry.
+		{"out/L2/translate.go", "return Ref{Base: TranslateAs[Expr](t, n.Base), Index: translatePtr(n.Index, func(x L1.Expr) Expr { return TranslateAs[Expr](t, x) })}", true},
+	}
+	for _, tt := range tests {
+		if got := strings.Contains(src[tt.path], tt.want); got != tt.ok {
+			t.Errorf("%v contains %q: %v, want %v", tt.path, tt.want, got, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// walk returns the source for L's Walk and Inspect functions, which
+// are modeled after go/ast's functions of the same names.
+func (L lang) walk() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// Code generated by Hermes. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", L.pkg)
+
+	fmt.Fprintf(&b, `// A Visitor's Visit method is invoked for each node encountered by
+// Walk. If the result visitor w is not nil, Walk visits each of the
+// children of node with the visitor w, followed by a call of
+// w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses a syntax tree in depth-first order: It starts by
+// calling v.Visit(node); node must not be nil. If the visitor w
+// returned by v.Visit(node) is not nil, Walk is invoked recursively
+// with visitor w for each of the non-nil children of node, followed
+// by a call of w.Visit(nil).
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+`)
+
+	for _, n := range L.nodes() {
+		var cas strings.Builder
+		for _, field := range n.fields {
+			L.walkField(&cas, "n."+field.Name(), field.Type())
+		}
+		if cas.Len() != 0 {
+			fmt.Fprintf(&b, "case %v:\n%v", n.name, cas.String())
+		}
+	}
+
+	fmt.Fprintf(&b, `}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses a syntax tree in depth-first order: It starts by
+// calling f(node); node must not be nil. If f returns true, Inspect
+// invokes f recursively for each of the non-nil children of node,
+// followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+`)
+
+	return b.String()
+}
+
+// walkField writes the statements that walk the children within x,
+// an expression of type typ.
+func (L lang) walkField(b *strings.Builder, x string, typ types.Type) {
+	switch typ := typ.(type) {
+	case *types.TypeParam:
+		switch def := L.defs[typ.Obj().Name()].(type) {
+		case *term:
+			fmt.Fprintf(b, "Walk(v, %v)\n", x)
+		case *nonterm:
+			if def.str != nil {
+				fmt.Fprintf(b, "Walk(v, %v)\n", x)
+			} else {
+				fmt.Fprintf(b, "if %v != nil {\nWalk(v, %v)\n}\n", x, x)
+			}
+		}
+	case *types.Slice:
+		var elem strings.Builder
+		L.walkField(&elem, "x", typ.Elem())
+		if elem.Len() != 0 {
+			fmt.Fprintf(b, "for _, x := range %v {\n%v}\n", x, elem.String())
+		}
+	case *types.Pointer:
+		var elem strings.Builder
+		L.walkField(&elem, "*"+x, typ.Elem())
+		if elem.Len() != 0 {
+			fmt.Fprintf(b, "if %v != nil {\n%v}\n", x, elem.String())
+		}
+	}
+}
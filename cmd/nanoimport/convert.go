@@ -0,0 +1,735 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/mdempsky/hermes/sexpr"
+)
+
+// A converter converts a sequence of define-language forms.
+type converter struct {
+	filename string
+	langs    map[string]*language
+	decls    []string // converted type declarations
+	last     string   // name of the last converted language
+}
+
+// A language is the state of a converted language, as needed to
+// convert the languages that extend it. All names are Go names,
+// except for metavariables.
+type language struct {
+	name     string
+	entry    string
+	terms    map[string]bool
+	nonterms map[string]*nonterm
+	metas    map[string]string // metavariable -> terminal or non-terminal
+	products map[string]string // product type -> its struct type
+	cons     map[string]string // production -> owning non-terminal
+}
+
+type nonterm struct {
+	prods []*prod
+}
+
+// A prod is a production of a non-terminal.
+type prod struct {
+	key    string // pattern, with metavariables replaced by what they name
+	embed  string // for a bare metavariable, the definition it names
+	name   string // otherwise, the production's name
+	fields []field
+}
+
+type field struct{ name, typ string }
+
+func (L *language) copy(name string) *language {
+	res := &language{
+		name:     name,
+		entry:    L.entry,
+		terms:    clone(L.terms),
+		nonterms: make(map[string]*nonterm, len(L.nonterms)),
+		metas:    clone(L.metas),
+		products: clone(L.products),
+		cons:     clone(L.cons),
+	}
+	for ntName, nt := range L.nonterms {
+		res.nonterms[ntName] = &nonterm{prods: slices.Clone(nt.prods)}
+	}
+	return res
+}
+
+func clone[K comparable, V any](m map[K]V) map[K]V {
+	res := make(map[K]V, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
+
+// A delta accumulates the type parameters of a converted language.
+type delta struct {
+	extends                string // parent, unless it's the previous language
+	define, redefine, omit []string
+	entry                  string // non-terminal declared by an entry command
+	ifaces                 []string
+	lines                  map[string][]string // interface -> its elements
+	products               []string
+	refs                   map[string]bool // referenced definitions
+}
+
+func (d *delta) iface(ntName string) {
+	if _, ok := d.lines[ntName]; !ok {
+		d.ifaces = append(d.ifaces, ntName)
+		d.lines[ntName] = nil
+	}
+}
+
+func (d *delta) add(ntName, line string) {
+	d.iface(ntName)
+	d.lines[ntName] = append(d.lines[ntName], line)
+}
+
+func (c *converter) errorf(x sexpr.Expr, format string, args ...any) {
+	log.Fatalf("%v:%v: %v", c.filename, x.Pos(), fmt.Sprintf(format, args...))
+}
+
+// convert converts a define-language form.
+func (c *converter) convert(form *sexpr.List) {
+	if len(form.Elems) < 2 {
+		c.errorf(form, "malformed define-language")
+	}
+	name := goName(c.atom(form.Elems[1]))
+	if c.langs[name] != nil {
+		c.errorf(form.Elems[1], "%v is already defined", name)
+	}
+
+	// Find the language this one extends, if any.
+	var L0 *language
+	for _, clause := range form.Elems[2:] {
+		if args, ok := c.clause(clause, "extends"); ok {
+			if len(args) != 1 {
+				c.errorf(clause, "malformed extends clause")
+			}
+			if L0 = c.langs[goName(c.atom(args[0]))]; L0 == nil {
+				c.errorf(args[0], "%v is not defined", c.atom(args[0]))
+			}
+		}
+	}
+
+	L := &language{
+		name:     name,
+		terms:    make(map[string]bool),
+		nonterms: make(map[string]*nonterm),
+		metas:    make(map[string]string),
+		products: make(map[string]string),
+		cons:     make(map[string]string),
+	}
+	if L0 != nil {
+		L = L0.copy(name)
+	} else {
+		L0 = &language{}
+	}
+	c.langs[name] = L
+
+	d := &delta{lines: make(map[string][]string), refs: make(map[string]bool)}
+	if L0.name != "" && L0.name != c.last {
+		d.extends = L0.name
+	}
+	c.last = name
+
+	// Terminals.
+	removed := make(map[string]bool)
+	for _, clause := range form.Elems[2:] {
+		specs, ok := c.clause(clause, "terminals")
+		if !ok {
+			continue
+		}
+		for _, spec := range specs {
+			if ext, ok := c.extension(spec); ok && ext == "-" {
+				for _, spec := range c.terminals(spec.(*sexpr.List).Elems[1:]) {
+					termName := goName(spec.name)
+					if !L.terms[termName] {
+						c.errorf(spec.pos, "%v is not a terminal of %v", spec.name, L0.name)
+					}
+					delete(L.terms, termName)
+					L.unmeta(termName)
+					removed[termName] = true
+				}
+			}
+		}
+		for _, spec := range c.terminals(specs) {
+			termName := goName(spec.name)
+			if L.terms[termName] || L.nonterms[termName] != nil {
+				c.errorf(spec.pos, "%v is already defined", spec.name)
+			}
+			L.terms[termName] = true
+			for _, meta := range spec.metas {
+				L.metas[meta] = termName
+			}
+			if removed[termName] {
+				d.redefine = append(d.redefine, termName)
+				delete(removed, termName)
+			} else {
+				d.define = append(d.define, termName)
+			}
+		}
+	}
+	for _, termName := range keys(removed) {
+		d.omit = append(d.omit, termName)
+	}
+
+	// Non-terminals: first their names and metavariables, so that
+	// productions can refer to later ones.
+	type ntClause struct {
+		name  string
+		elems []sexpr.Expr // productions, or extensions
+	}
+	var nts []ntClause
+	for _, clause := range form.Elems[2:] {
+		l, ok := clause.(*sexpr.List)
+		if !ok || len(l.Elems) < 2 {
+			c.errorf(clause, "malformed clause")
+		}
+		switch c.atom(l.Elems[0]) {
+		case "extends", "terminals", "entry", "nongenerative-id":
+			continue
+		}
+		ntName := goName(c.atom(l.Elems[0]))
+		if L.terms[ntName] {
+			c.errorf(l.Elems[0], "%v is already a terminal", ntName)
+		}
+		if L.nonterms[ntName] == nil {
+			L.nonterms[ntName] = &nonterm{}
+		}
+		L.unmeta(ntName)
+		metas, ok := l.Elems[1].(*sexpr.List)
+		if !ok {
+			c.errorf(l.Elems[1], "expected metavariables, found %v", l.Elems[1])
+		}
+		for _, meta := range metas.Elems {
+			L.metas[c.atom(meta)] = ntName
+		}
+		nts = append(nts, ntClause{ntName, l.Elems[2:]})
+	}
+
+	// Then their productions: removals, so that their names are free
+	// again, and then additions.
+	var adds []ntClause
+	removedCons := make(map[string]bool)
+	for _, clause := range nts {
+		nt := L.nonterms[clause.name]
+		var add []sexpr.Expr
+		for _, elem := range unparsers(clause.elems) {
+			ext, ok := c.extension(elem)
+			if !ok {
+				if L0.name != "" {
+					c.errorf(elem, "expected (+ ...) or (- ...) in a language that extends another")
+				}
+				add = append(add, elem)
+				continue
+			}
+			pats := unparsers(elem.(*sexpr.List).Elems[1:])
+			if ext == "+" {
+				add = append(add, pats...)
+				continue
+			}
+			for _, pat := range pats {
+				key := c.key(L0, pat)
+				i := slices.IndexFunc(nt.prods, func(p *prod) bool { return p.key == key })
+				if i < 0 {
+					c.errorf(pat, "%v has no production %v in %v", clause.name, pat, L0.name)
+				}
+				p := nt.prods[i]
+				nt.prods = slices.Delete(nt.prods, i, i+1)
+				if p.name == "" {
+					d.add(clause.name, "*"+p.embed+" | omit")
+					d.refs[p.embed] = true
+					continue
+				}
+				delete(L.cons, p.name)
+				removedCons[p.name] = true
+				d.iface(clause.name)
+			}
+		}
+		adds = append(adds, ntClause{clause.name, add})
+	}
+	for _, clause := range adds {
+		nt := L.nonterms[clause.name]
+		d.iface(clause.name)
+		for _, pat := range clause.elems {
+			p := c.prod(L, d, clause.name, pat)
+			if slices.ContainsFunc(nt.prods, func(q *prod) bool { return q.key == p.key }) {
+				c.errorf(pat, "%v already has production %v", clause.name, pat)
+			}
+			nt.prods = append(nt.prods, p)
+			if p.name == "" {
+				d.add(clause.name, "*"+p.embed)
+				d.refs[p.embed] = true
+				continue
+			}
+			delete(removedCons, p.name)
+			d.add(clause.name, fmt.Sprintf("%v(%v)", p.name, params(p.fields)))
+		}
+	}
+
+	// Productions that were removed and not added back.
+	for _, conName := range keys(removedCons) {
+		if ntName, ok := L0.cons[conName]; ok && L.nonterms[ntName] != nil {
+			d.add(ntName, conName+"() omit")
+		}
+	}
+
+	// Non-terminals with no productions left are omitted entirely.
+	for _, ntName := range keys(L.nonterms) {
+		if len(L.nonterms[ntName].prods) == 0 && L0.nonterms[ntName] != nil {
+			delete(L.nonterms, ntName)
+			L.unmeta(ntName)
+			d.omit = append(d.omit, ntName)
+			d.ifaces = slices.DeleteFunc(d.ifaces, func(s string) bool { return s == ntName })
+		}
+	}
+
+	// The entry defaults to the first non-terminal.
+	for _, clause := range form.Elems[2:] {
+		if args, ok := c.clause(clause, "entry"); ok {
+			if len(args) != 1 {
+				c.errorf(clause, "malformed entry clause")
+			}
+			if L.entry = goName(c.atom(args[0])); L.nonterms[L.entry] == nil {
+				c.errorf(args[0], "entry %v is not a non-terminal", c.atom(args[0]))
+			}
+		}
+	}
+	if L.entry == "" && len(nts) != 0 {
+		L.entry = nts[0].name
+	}
+
+	// mklang omits definitions that become unreachable from the entry
+	// by itself, and warns about explicit omits of them.
+	reached := reachable(L.entry, L, L0)
+	d.omit = slices.DeleteFunc(d.omit, func(name string) bool { return !reached[name] })
+	if L.entry != L0.entry || len(d.ifaces)+len(d.define)+len(d.redefine)+len(d.omit)+len(d.products) == 0 {
+		if _, ok := d.lines[L.entry]; ok {
+			d.lines[L.entry] = append([]string{"entry"}, d.lines[L.entry]...)
+		} else {
+			d.entry = L.entry
+		}
+	}
+
+	c.decls = append(c.decls, d.source(L))
+}
+
+// reachable returns the definitions reachable from entry by way of
+// productions, embeddings, and fields. Definitions are looked up in
+// L, or else in L0, so that those that L omits are still followed.
+func reachable(entry string, L, L0 *language) map[string]bool {
+	reached := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		name = strings.TrimLeft(name, "[]*")
+		if reached[name] {
+			return
+		}
+		reached[name] = true
+
+		nt := L.nonterms[name]
+		if nt == nil {
+			nt = L0.nonterms[name]
+		}
+		if nt != nil {
+			for _, p := range nt.prods {
+				if p.embed != "" {
+					visit(p.embed)
+				}
+				for _, f := range p.fields {
+					visit(f.typ)
+				}
+			}
+		}
+
+		str, ok := L.products[name]
+		if !ok {
+			str = L0.products[name]
+		}
+		for _, line := range strings.Split(str, "\n") {
+			if f := strings.Fields(line); len(f) == 2 && f[1] != "{" {
+				visit(f[1])
+			}
+		}
+	}
+	if entry != "" {
+		visit(entry)
+	}
+	return reached
+}
+
+// source returns the type declaration for L, given its delta d.
+func (d *delta) source(L *language) string {
+	declared := make(map[string]bool)
+	var params []string
+	group := func(names []string, constraint string) {
+		if len(names) != 0 {
+			params = append(params, strings.Join(names, ", ")+" "+constraint)
+			for _, name := range names {
+				declared[name] = true
+			}
+		}
+	}
+
+	if d.extends != "" {
+		params = append(params, d.extends+" extends")
+	}
+	group(d.omit, "omit")
+	group(d.define, "define")
+	group(d.redefine, "redefine")
+	if d.entry != "" {
+		group([]string{d.entry}, "entry")
+	}
+	for _, ntName := range d.ifaces {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%v interface {\n", ntName)
+		for _, line := range d.lines[ntName] {
+			fmt.Fprintf(&b, "%v\n", line)
+		}
+		b.WriteString("}")
+		params = append(params, b.String())
+		declared[ntName] = true
+	}
+	for _, name := range d.products {
+		params = append(params, name+" "+L.products[name])
+		declared[name] = true
+	}
+
+	var inherit []string
+	for _, name := range keys(d.refs) {
+		if !declared[name] {
+			inherit = append(inherit, name)
+		}
+	}
+	group(inherit, "inherit")
+
+	return fmt.Sprintf("type %v[\n%v,\n] language", L.name, strings.Join(params, ",\n"))
+}
+
+// source returns the Go source for the converted languages.
+func (c *converter) source() []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Language declarations converted from %v by nanoimport.\n\n", c.filename)
+	fmt.Fprintf(&b, "//go:generate go run github.com/mdempsky/hermes/cmd/mklang\n\n")
+	fmt.Fprintf(&b, "package %v\n\n", *pkgFlag)
+	for _, kw := range []string{"omit", "inherit", "define", "redefine", "entry", "extends", "language"} {
+		fmt.Fprintf(&b, "type %v any\n", kw)
+	}
+	for _, decl := range c.decls {
+		fmt.Fprintf(&b, "\n%v\n", decl)
+	}
+	return []byte(b.String())
+}
+
+// unmeta removes the metavariables that refer to the named
+// definition.
+func (L *language) unmeta(defName string) {
+	for meta, name := range L.metas {
+		if name == defName {
+			delete(L.metas, meta)
+		}
+	}
+}
+
+// prod converts pat, a production of the named non-terminal.
+func (c *converter) prod(L *language, d *delta, ntName string, pat sexpr.Expr) *prod {
+	p := &prod{key: c.key(L, pat)}
+
+	l, ok := pat.(*sexpr.List)
+	if !ok {
+		p.embed = c.meta(L, pat)
+		return p
+	}
+	if len(l.Elems) == 0 {
+		c.errorf(pat, "empty production")
+	}
+
+	// A production without a keyword, like (e e* ...), is an
+	// application.
+	base, elems := "Apply", l.Elems
+	if a, ok := l.Elems[0].(*sexpr.Atom); ok && L.metaDef(a.Text) == "" {
+		base, elems = goName(a.Text), l.Elems[1:]
+	}
+
+	// Productions are Go types, so their names must be unique within
+	// a language. Disambiguate them by non-terminal, or by arity.
+	p.name = base
+	for i := 2; L.cons[p.name] != ""; i++ {
+		if L.cons[p.name] != ntName && L.cons[base+ntName] == "" {
+			p.name = base + ntName
+		} else {
+			p.name = fmt.Sprintf("%v%d", base, i)
+		}
+	}
+	L.cons[p.name] = ntName
+
+	p.fields = c.fields(L, d, p.name, elems)
+	return p
+}
+
+// fields converts the elements of a pattern into fields, creating
+// product types named after con as necessary.
+func (c *converter) fields(L *language, d *delta, con string, elems []sexpr.Expr) []field {
+	var res []field
+	used := make(map[string]bool)
+	for i := 0; i < len(elems); i++ {
+		f := c.field(L, d, con, elems[i])
+		if i+1 < len(elems) && isEllipsis(elems[i+1]) {
+			f.name = plural(f.name)
+			f.typ = "[]" + f.typ
+			i++
+		}
+		name := f.name
+		for j := 2; used[f.name]; j++ {
+			f.name = fmt.Sprintf("%v%d", name, j)
+		}
+		used[f.name] = true
+		res = append(res, f)
+	}
+	return res
+}
+
+// field converts a single element of a pattern into a field.
+func (c *converter) field(L *language, d *delta, con string, elem sexpr.Expr) field {
+	switch elem := elem.(type) {
+	case *sexpr.Atom:
+		if isEllipsis(elem) {
+			c.errorf(elem, "unexpected ellipsis")
+		}
+		defName := c.meta(L, elem)
+		d.refs[defName] = true
+		return field{goName(strings.TrimRight(elem.Text, "*^?")), defName}
+	case *sexpr.List:
+		if len(elem.Elems) == 2 && isAtom(elem.Elems[0], "maybe") {
+			f := c.field(L, d, con, elem.Elems[1])
+			f.typ = "*" + f.typ
+			return f
+		}
+		fields := c.fields(L, d, con, elem.Elems)
+		switch len(fields) {
+		case 0:
+			c.errorf(elem, "empty pattern")
+		case 1:
+			return fields[0]
+		}
+
+		// A product type, named after the production.
+		var b strings.Builder
+		b.WriteString("struct {\n")
+		for _, f := range fields {
+			fmt.Fprintf(&b, "%v %v\n", f.name, f.typ)
+		}
+		b.WriteString("}")
+		// Reuse an existing product type with the same fields, or
+		// else redefine one inherited from the extended language.
+		name := con + "Binding"
+		for i := 2; L.products[name] != b.String(); i++ {
+			if !slices.Contains(d.products, name) {
+				L.products[name] = b.String()
+				d.products = append(d.products, name)
+				break
+			}
+			name = fmt.Sprintf("%vBinding%d", con, i)
+		}
+		d.refs[name] = true
+		return field{"Binding", name}
+	}
+	panic("unreachable")
+}
+
+// params returns a parameter list declaring fields, grouping the
+// names of consecutive fields of the same type.
+func params(fields []field) string {
+	var b strings.Builder
+	for i, f := range fields {
+		b.WriteString(f.name)
+		if i+1 < len(fields) && fields[i+1].typ == f.typ {
+			b.WriteString(", ")
+			continue
+		}
+		b.WriteString(" " + f.typ)
+		if i+1 < len(fields) {
+			b.WriteString(", ")
+		}
+	}
+	return b.String()
+}
+
+// plural returns the plural of the field name s.
+func plural(s string) string {
+	if n := len(s); n >= 2 && s[n-1] == 'y' && !strings.ContainsRune("aeiou", rune(s[n-2])) {
+		return s[:n-1] + "ies"
+	}
+	return s + "s"
+}
+
+// key returns a key for pat, a production of L, that identifies it
+// regardless of the spelling of its metavariables.
+func (c *converter) key(L *language, pat sexpr.Expr) string {
+	switch pat := pat.(type) {
+	case *sexpr.Atom:
+		if defName := L.metaDef(pat.Text); defName != "" {
+			return defName
+		}
+		return pat.Text
+	case *sexpr.List:
+		var elems []string
+		for _, elem := range pat.Elems {
+			elems = append(elems, c.key(L, elem))
+		}
+		return "(" + strings.Join(elems, " ") + ")"
+	}
+	panic("unreachable")
+}
+
+// meta returns the definition named by the metavariable x.
+func (c *converter) meta(L *language, x sexpr.Expr) string {
+	defName := L.metaDef(c.atom(x))
+	if defName == "" {
+		c.errorf(x, "unknown metavariable %v", x)
+	}
+	return defName
+}
+
+// metaDef returns the definition named by s, a metavariable with an
+// optional suffix of digits, asterisks, carets, and question marks, or
+// "" if there is none. The suffix is only removed if s isn't itself a
+// metavariable, like int64.
+func (L *language) metaDef(s string) string {
+	if defName, ok := L.metas[s]; ok {
+		return defName
+	}
+	return L.metas[strings.TrimRightFunc(s, func(r rune) bool {
+		return unicode.IsDigit(r) || r == '*' || r == '^' || r == '?'
+	})]
+}
+
+// A termSpec is a terminal declaration.
+type termSpec struct {
+	pos   sexpr.Expr
+	name  string
+	metas []string
+}
+
+// terminals returns the terminal declarations in specs, including
+// those added by (+ ...) extensions.
+func (c *converter) terminals(specs []sexpr.Expr) []termSpec {
+	var res []termSpec
+	for _, spec := range unparsers(specs) {
+		if ext, ok := c.extension(spec); ok {
+			if ext == "+" {
+				res = append(res, c.terminals(spec.(*sexpr.List).Elems[1:])...)
+			}
+			continue
+		}
+		l, ok := spec.(*sexpr.List)
+		if !ok || len(l.Elems) < 2 {
+			c.errorf(spec, "malformed terminal %v", spec)
+		}
+		metas, ok := l.Elems[1].(*sexpr.List)
+		if !ok {
+			c.errorf(l.Elems[1], "expected metavariables, found %v", l.Elems[1])
+		}
+		ts := termSpec{pos: spec, name: c.atom(l.Elems[0])}
+		for _, meta := range metas.Elems {
+			ts.metas = append(ts.metas, c.atom(meta))
+		}
+		res = append(res, ts)
+	}
+	return res
+}
+
+// unparsers returns elems without any "=> unparser" annotations.
+func unparsers(elems []sexpr.Expr) []sexpr.Expr {
+	var res []sexpr.Expr
+	for i := 0; i < len(elems); i++ {
+		if isAtom(elems[i], "=>") {
+			i++
+			continue
+		}
+		res = append(res, elems[i])
+	}
+	return res
+}
+
+// clause reports whether x is a clause with the given keyword, and
+// if so, returns its arguments.
+func (c *converter) clause(x sexpr.Expr, keyword string) ([]sexpr.Expr, bool) {
+	if l, ok := x.(*sexpr.List); ok && len(l.Elems) != 0 && isAtom(l.Elems[0], keyword) {
+		return l.Elems[1:], true
+	}
+	return nil, false
+}
+
+// extension reports whether x is a (+ ...) or (- ...) clause of a
+// language that extends another, and if so, which.
+func (c *converter) extension(x sexpr.Expr) (string, bool) {
+	for _, ext := range []string{"+", "-"} {
+		if _, ok := c.clause(x, ext); ok {
+			return ext, true
+		}
+	}
+	return "", false
+}
+
+// atom returns the text of x, which must be an atom.
+func (c *converter) atom(x sexpr.Expr) string {
+	a, ok := x.(*sexpr.Atom)
+	if !ok {
+		c.errorf(x, "expected identifier, found %v", x)
+	}
+	return a.Text
+}
+
+func isAtom(x sexpr.Expr, text string) bool {
+	a, ok := x.(*sexpr.Atom)
+	return ok && a.Text == text
+}
+
+func isEllipsis(x sexpr.Expr) bool { return isAtom(x, "...") }
+
+// goName returns a Go identifier for the Scheme identifier s, by
+// capitalizing each of its words and dropping punctuation. For
+// example, "set!" becomes "Set" and "make-vector" becomes
+// "MakeVector".
+func goName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if upper {
+				r = unicode.ToUpper(r)
+			}
+			b.WriteRune(r)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	if b.Len() == 0 || !unicode.IsLetter([]rune(b.String())[0]) {
+		return "X" + b.String()
+	}
+	return b.String()
+}
+
+func keys[K interface{ ~string }, V any](m map[K]V) []K {
+	res := make([]K, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	slices.Sort(res)
+	return res
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{
+			name: "metavariable suffixes",
+			src: `
+(define-language L0
+  (terminals (symbol (x)) (int64 (int64)))
+  (Expr (e)
+    x
+    (const int64)
+    (if e0 e1 e2)
+    (apply e e* ...)
+    (e0 e1)))
+`,
+			want: `
+type L0[
+	Symbol, Int64 define,
+	Expr interface {
+		entry
+		*Symbol
+		Const(Int64 Int64)
+		If(E0, E1, E2 Expr)
+		Apply(E Expr, Es []Expr)
+		Apply2(E0, E1 Expr)
+	},
+] language
+`,
+		},
+		{
+			name: "unreachable omits",
+			src: `
+(define-language L0
+  (terminals (symbol (x)) (int (n)))
+  (Expr (e)
+    x
+    (const c)
+    (begin e* ... e))
+  (Const (c)
+    (int n)))
+
+(define-language L1
+  (extends L0)
+  (terminals (- (int (n))))
+  (Expr (e)
+    (- (const c)))
+  (Const (c)
+    (- (int n))))
+`,
+			want: `
+type L0[
+	Symbol, Int define,
+	Expr interface {
+		entry
+		*Symbol
+		Const(C Const)
+		Begin(Es []Expr, E Expr)
+	},
+	Const interface {
+		Int(N Int)
+	},
+] language
+
+type L1[
+	Expr interface {
+		Const() omit
+	},
+] language
+`,
+		},
+		{
+			name: "replaced terminal",
+			src: `
+(define-language L0
+  (terminals (symbol (x)))
+  (Expr (e)
+    x
+    (let ([x e]) body))
+  (Body (body)
+    e))
+
+(define-language L1
+  (extends L0)
+  (terminals (- (symbol (x))) (+ (var (v))))
+  (Expr (e)
+    (- x (let ([x e]) body))
+    (+ v (let ([v e]) body))))
+
+(define-language L2
+  (extends L0)
+  (Expr (e)
+    (+ (nop))))
+`,
+			want: `
+type L0[
+	Symbol define,
+	Expr interface {
+		entry
+		*Symbol
+		Let(Binding LetBinding, Body Body)
+	},
+	Body interface {
+		*Expr
+	},
+	LetBinding struct {
+		X Symbol
+		E Expr
+	},
+] language
+
+type L1[
+	Var define,
+	Expr interface {
+		*Symbol | omit
+		*Var
+		Let(Binding LetBinding, Body Body)
+	},
+	LetBinding struct {
+		V Var
+		E Expr
+	},
+	Body, Symbol inherit,
+] language
+
+type L2[
+	L0 extends,
+	Expr interface {
+		Nop()
+	},
+] language
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := converter{filename: "test.ss", langs: make(map[string]*language)}
+			for _, form := range defineLanguages("test.ss", tt.src) {
+				c.convert(form)
+			}
+			got := gofmt(t, strings.Join(c.decls, "\n\n"))
+			if want := gofmt(t, tt.want); got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+// gofmt returns the declarations in src, formatted.
+func gofmt(t *testing.T, src string) string {
+	t.Helper()
+	out, err := format.Source([]byte("package lang\n\n" + strings.TrimSpace(src) + "\n"))
+	if err != nil {
+		t.Fatalf("format error: %v\n%s", err, src)
+	}
+	return string(out)
+}
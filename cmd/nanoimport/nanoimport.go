@@ -0,0 +1,191 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Nanoimport converts the define-language forms of a nanopass
+// compiler into Go-syntax language declarations, in the style of
+// example/lang.go, for use with mklang.
+//
+// Usage:
+//
+//	nanoimport [-o file] [-pkg name] file.ss
+//
+// Only the define-language forms are read; the rest of the file is
+// ignored. Languages are converted in the order they're defined, and
+// a language's extends clause must name one defined earlier. If that's
+// not the language just before it, the declaration names its parent,
+// as in "L8 extends".
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mdempsky/hermes/sexpr"
+)
+
+var (
+	outFlag = flag.String("o", "", "write the declarations to `file` instead of standard output")
+	pkgFlag = flag.String("pkg", "lang", "package `name` for the declarations")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("nanoimport: ")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: nanoimport [flags] file.ss\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	filename := flag.Arg(0)
+
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	c := converter{filename: filename, langs: make(map[string]*language)}
+	for _, form := range defineLanguages(filename, string(buf)) {
+		c.convert(form)
+	}
+
+	src, err := format.Source(c.source())
+	if err != nil {
+		log.Fatalf("format error: %v", err)
+	}
+
+	if *outFlag == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*outFlag, src, 0666); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// defineLanguages returns the define-language forms within src, the
+// contents of the named file. They may be nested within other forms,
+// such as a library.
+func defineLanguages(filename, src string) []*sexpr.List {
+	clean := blank(src)
+
+	var res []*sexpr.List
+	for start := 0; start < len(clean); start++ {
+		if c := clean[start]; c != '(' && c != '[' {
+			continue
+		}
+		if head := strings.Fields(clean[start+1:]); len(head) == 0 || head[0] != "define-language" {
+			continue
+		}
+		end := closing(clean, start)
+
+		// Parse the form by itself, but with everything else blanked
+		// out, so that positions are relative to the whole file.
+		only := blankRange(blankRange(clean, 0, start), end, len(clean))
+		x, err := sexpr.Parse(only)
+		if err != nil {
+			if err, ok := err.(*sexpr.Error); ok {
+				log.Fatalf("%v:%v: %v", filename, err.Pos, err.Msg)
+			}
+			log.Fatalf("%v: %v", filename, err)
+		}
+		res = append(res, x.(*sexpr.List))
+		start = end - 1
+	}
+	return res
+}
+
+// blank returns src with its comments, strings, and character
+// literals replaced by spaces, except for newlines, so that what
+// remains can be read as s-expressions without disturbing positions.
+func blank(src string) string {
+	b := []byte(src)
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == ';':
+			j := i
+			for j < len(b) && b[j] != '\n' {
+				j++
+			}
+			blankBytes(b[i:j])
+			i = j
+		case b[i] == '"':
+			j := i + 1
+			for j < len(b) && b[j] != '"' {
+				if b[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(b))
+			blankBytes(b[i:j])
+			i = j - 1
+		case b[i] == '#' && i+1 < len(b) && b[i+1] == '\\':
+			j := min(i+3, len(b))
+			blankBytes(b[i:j])
+			i = j - 1
+		case b[i] == '#' && i+1 < len(b) && b[i+1] == '|':
+			depth := 0
+			j := i
+			for j < len(b) {
+				if bytes.HasPrefix(b[j:], []byte("#|")) {
+					depth++
+					j += 2
+				} else if bytes.HasPrefix(b[j:], []byte("|#")) {
+					j += 2
+					if depth--; depth == 0 {
+						break
+					}
+				} else {
+					j++
+				}
+			}
+			blankBytes(b[i:j])
+			i = j - 1
+		}
+	}
+	return string(b)
+}
+
+func blankBytes(b []byte) {
+	for i, c := range b {
+		if c != '\n' {
+			b[i] = ' '
+		}
+	}
+}
+
+// blankRange returns s with s[start:end] blanked out.
+func blankRange(s string, start, end int) string {
+	b := []byte(s)
+	blankBytes(b[start:end])
+	return string(b)
+}
+
+// closing returns the offset just past the end of the list starting
+// at src[start], which must already be blanked, or len(src) if it's
+// unclosed.
+func closing(src string, start int) int {
+	depth := 0
+	for i := start; i < len(src); i++ {
+		switch src[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(src)
+}
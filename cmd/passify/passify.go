@@ -63,6 +63,13 @@ func do(file string, pkgs []*packages.Package) {
 		// Their corresponding packages.
 		Lsrc = src.Obj().Pkg()
 		Ldst = dst.Obj().Pkg()
+
+		// Check them against the languages' declared entry types.
+		for _, typ := range []*types.Named{src, dst} {
+			if entry := typ.Obj().Pkg().Scope().Lookup("Entry"); entry != nil && !types.Identical(types.Unalias(entry.Type()), typ) {
+				log.Fatalf("%v: Entry uses %v, but the entry of %v is %v", file, typ, typ.Obj().Pkg().Name(), types.Unalias(entry.Type()))
+			}
+		}
 		fmt.Printf("\n### %v: %v -> %v\n", file, Lsrc.Name(), Ldst.Name())
 	}
 
@@ -89,8 +96,10 @@ func do(file string, pkgs []*packages.Package) {
 		if srcUnder, ok := srcType.Type().(*types.Named).Underlying().(*types.Struct); ok {
 
 			sig := fn.Type().(*types.Signature)
-			if sig.Params().Len() != srcUnder.NumFields() {
-				log.Fatalf("bad signature: cannot morph %v fields into %v parameters", srcUnder.NumFields(), sig.Params().Len())
+			// The metadata field, if any, isn't a parameter: it's
+			// carried over to the result, as by the Translator.
+			if n := syntaxFields(Lsrc, srcUnder); sig.Params().Len() != n {
+				log.Fatalf("bad signature: cannot morph %v fields into %v parameters", n, sig.Params().Len())
 			}
 
 			env := make(map[ssa.Value]Expr)
@@ -309,3 +318,29 @@ func keys[K cmp.Ordered, V any](m map[K]V) []K {
 	slices.Sort(res)
 	return res
 }
+
+// syntaxFields returns the number of fields of str, a production or
+// product type of the generated package L, not counting its metadata
+// field, which mklang adds last.
+func syntaxFields(L *types.Package, str *types.Struct) int {
+	n := str.NumFields()
+	if n > 0 {
+		if named, ok := str.Field(n - 1).Type().(*types.Named); ok && named.Obj().Pkg() == langPkg(L) && named.Obj().Name() == "Meta" {
+			n--
+		}
+	}
+	return n
+}
+
+// langPkg returns the lang package that describes the generated
+// package L: the package of the type of its Language variable.
+func langPkg(L *types.Package) *types.Package {
+	if v, ok := L.Scope().Lookup("Language").(*types.Var); ok {
+		if ptr, ok := v.Type().(*types.Pointer); ok {
+			if named, ok := ptr.Elem().(*types.Named); ok {
+				return named.Obj().Pkg()
+			}
+		}
+	}
+	return nil
+}
@@ -2,26 +2,95 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+//go:generate go run github.com/mdempsky/hermes/cmd/mklang
+
 package lang
 
+import "github.com/mdempsky/hermes/example/term"
+
+// omit removes a definition, production, or embedding ("*X | omit"),
+// which are otherwise inherited. Definitions that become unreachable
+// from the entry are omitted automatically.
 type omit any
 type inherit any
+
+// define declares a terminal, represented as an int, or by R if
+// declared as "interface{ define; R }" and valid if R's Valid method
+// (if any) says so.
 type define any
 type redefine any
-type language any
 
-// TODO(mdempsky): Do I want an explicit "entry" point?
-//
-// In scheme-to-c, it only changes once, from "Expr" to "Program".
+// entry marks a language's entry non-terminal, which is inherited
+// unless redeclared. In scheme-to-c, it only changes once, from "Expr"
+// to "Program".
+type entry any
+type language any
 
-// TODO(mdempsky): I'm adding some explicit "omits" that I think
-// should be inferred automatically.
+// Primitive classes and flags. A primitive table is a terminal whose
+// methods declare its primitives, with their arity, class, and flags;
+// "*X" embeds the primitives of table X, restricted to any embedded
+// classes, and "K | omit" removes those with class or flag K.
+type value any
+type effect any
+type predicate any
+type pure any
+type alloc any
+
+// Binding roles of fields. A bind[T] field binds the variables it
+// holds within the scope[T] fields of the same production, including
+// when it's a field of a product type, like Binding.Var. A bind[N]
+// field of a non-terminal N binds the binders of its value, whose
+// scope[T] fields are within the scope of the enclosing production.
+type bind[T any] any
+type scope[T any] any
+
+// Declares a metadata field, "M meta", of type lang.Meta[any] on every
+// production, or lang.Meta[A] with "interface{ meta; A }".
+type meta any
+
+// Declares the language that a language extends, as in "L8 extends",
+// in place of the one declared before it.
+type extends any
 
 type Lsrc[
-	Primitive define,
-	Symbol define,
+	Primitive interface {
+		define
+		Add(x, y any) (value, pure)
+		Sub(x, y any) (value, pure)
+		Mul(x, y any) (value, pure)
+		Div(x, y any) (value, pure)
+		Car(p any) (value, pure)
+		Cdr(p any) (value, pure)
+		Cons(car, cdr any) (value, pure, alloc)
+		MakeVector(n any) (value, pure, alloc)
+		VectorRef(v, i any) (value, pure)
+		VectorLength(v any) (value, pure)
+		Box(x any) (value, pure, alloc)
+		Unbox(b any) (value, pure)
+		SetCar(p, x any) effect
+		SetCdr(p, x any) effect
+		VectorSet(v, i, x any) effect
+		SetBox(b, x any) effect
+		Lt(x, y any) (predicate, pure)
+		Le(x, y any) (predicate, pure)
+		NumEq(x, y any) (predicate, pure)
+		Ge(x, y any) (predicate, pure)
+		Gt(x, y any) (predicate, pure)
+		Eq(x, y any) (predicate, pure)
+		IsBoolean(x any) (predicate, pure)
+		IsBox(x any) (predicate, pure)
+		IsNull(x any) (predicate, pure)
+		IsPair(x any) (predicate, pure)
+		IsProcedure(x any) (predicate, pure)
+		IsVector(x any) (predicate, pure)
+	},
+	Symbol interface {
+		define
+		term.Symbol
+	},
 
 	Expr interface {
+		entry
 		*Primitive
 		*Symbol
 		*Const
@@ -32,14 +101,14 @@ type Lsrc[
 		And(X []Expr)
 		Not(X Expr)
 		Begin(Init []Expr, Body Expr)
-		Lambda(Params []Symbol, Init []Expr, Body Expr)
-		Let(Bindings []Binding, Init []Expr, Body Expr)
-		LetRec(Bindings []Binding, Init []Expr, Body Expr)
+		Lambda(Params bind[[]Symbol], Init scope[[]Expr], Body scope[Expr])
+		Let(Bindings []Binding, Init scope[[]Expr], Body scope[Expr])
+		LetRec(Bindings scope[[]Binding], Init scope[[]Expr], Body scope[Expr])
 		Set(Var Symbol, Val Expr)
 		Apply(Fun Expr, Args []Expr)
 	},
 	Binding struct {
-		Var Symbol
+		Var bind[Symbol]
 		Val Expr
 	},
 	Const interface {
@@ -53,13 +122,16 @@ type Lsrc[
 		Pair(Car, Cdr Datum)
 		Vector(List []Datum)
 	},
+	Meta meta,
 ] language
 
 // L1 removes one-armed if and adds the void primitive.
 type L1[
-	Primitive redefine, // adds Void
+	Primitive interface {
+		redefine
+		Void() (value, pure)
+	},
 	Expr interface {
-		*Primitive // keep embedding
 		IfThen() omit
 	},
 ] language
@@ -79,9 +151,9 @@ type L2[
 type L3[
 	Symbol, Binding inherit,
 	Expr interface {
-		Lambda(Params []Symbol, Body Expr)
-		Let(Bindings []Binding, Body Expr)
-		LetRec(Bindings []Binding, Body Expr)
+		Lambda(Params bind[[]Symbol], Body scope[Expr])
+		Let(Bindings []Binding, Body scope[Expr])
+		LetRec(Bindings scope[[]Binding], Body scope[Expr])
 	},
 ] language
 
@@ -90,7 +162,7 @@ type L3[
 type L4[
 	Primitive inherit,
 	Expr interface {
-		**Primitive // TODO(mdempsky): implicit?
+		*Primitive | omit
 		PrimCall(Prim Primitive, Args []Expr)
 	},
 ] language
@@ -99,10 +171,7 @@ type L4[
 type L5[
 	Const inherit,
 	Expr interface {
-		**Const
-	},
-	Datum interface {
-		*Const // preserve
+		*Const | omit
 	},
 ] language
 
@@ -119,9 +188,9 @@ type L6[
 // forms: let, letrec, and lambda.
 type L7[
 	Expr interface {
-		Lambda(Params []Symbol, Body AssignedBody)
-		Let(Bindings []Binding, Body AssignedBody)
-		LetRec(Bindings []Binding, Body AssignedBody)
+		Lambda(Params bind[[]Symbol], Body scope[AssignedBody])
+		Let(Bindings []Binding, Body scope[AssignedBody])
+		LetRec(Bindings scope[[]Binding], Body scope[AssignedBody])
 	},
 	// TODO(mdempsky): This seems more fitting as an attribute grammar.
 	AssignedBody struct {
@@ -134,12 +203,11 @@ type L7[
 // L8 changes letrec bindings to only bind variables to lambdas.
 type L8[
 	Expr interface {
-		*Symbol
 		*LambdaExpr
-		LetRec(Bindings []RecBinding, Body Expr)
+		LetRec(Bindings scope[[]RecBinding], Body scope[Expr])
 	},
 	RecBinding struct {
-		Var Symbol
+		Var bind[Symbol]
 		Val LambdaExpr
 	},
 	// TODO(mdempsky): What would be the trade-offs of representing this
@@ -148,7 +216,7 @@ type L8[
 	// I suppose if I did that, I wouldn't be allowed to embed it in
 	// Expr, though I'm getting rid of that soon anyway.
 	LambdaExpr interface {
-		Lambda(Params []Symbol, Body AssignedBody)
+		Lambda(Params bind[[]Symbol], Body scope[AssignedBody])
 	},
 	Symbol, AssignedBody inherit,
 ] language
@@ -158,7 +226,7 @@ type L8[
 // letrec expressions.
 type L9[
 	Expr interface {
-		**LambdaExpr
+		*LambdaExpr | omit
 	},
 	LambdaExpr inherit,
 ] language
@@ -167,13 +235,12 @@ type L9[
 // primcall for set!, and unbox primcall for references of assigned
 // variables).
 type L10[
-	AssignedBody omit, // asserts that this form is gone
 	Expr interface {
 		Set() omit
-		Let(Bindings []Binding, Body Expr)
+		Let(Bindings []Binding, Body scope[Expr])
 	},
 	LambdaExpr interface {
-		Lambda(Params []Symbol, Body Expr)
+		Lambda(Params bind[[]Symbol], Body scope[Expr])
 	},
 	Binding, Symbol inherit,
 ] language
@@ -183,7 +250,7 @@ type L10[
 type L11[
 	Symbol, Expr inherit,
 	LambdaExpr interface {
-		Lambda(Params []Symbol, Body FreeBody)
+		Lambda(Params bind[[]Symbol], Body scope[FreeBody])
 	},
 	FreeBody interface {
 		Free(Free []Symbol, Body Expr)
@@ -193,26 +260,25 @@ type L11[
 // L12 removes the letrec form and adds closure and labels forms to
 // replace it.  The closure form binds a variable to a label (code
 // pointer) and its set of free variables, and the labels form binds
-// labels (code pointer) to lambda expressions.
+// labels (code pointer) to lambda expressions.  The closures form
+// binds the labels of its labels form too, since its closures refer
+// to them.
 type L12[
-	// TODO(mdempsky): AssignedBody should be gone here.
-	AssignedBody omit,
-
 	Symbol inherit,
 	RecBinding inherit,
 
 	Expr interface {
 		LetRec() omit
 		Label(Name Symbol)
-		Closures(Closures []Closure, Body LabelsBody)
+		Closures(Closures scope[[]Closure], Body bind[LabelsBody])
 	},
 	Closure struct {
-		X Symbol
+		X bind[Symbol]
 		L Symbol
 		F []Symbol
 	},
 	LabelsBody interface {
-		Labels(Bindings []RecBinding, Body Expr)
+		Labels(Bindings scope[[]RecBinding], Body scope[Expr])
 	},
 ] language
 
@@ -220,18 +286,23 @@ type L12[
 // replacing it with primitive calls to deal with closure objects, and
 // raises the labels from into the Expr non-terminal.
 type L13[
-	FreeBody omit,
-
-	Primitive redefine, // adds Closure
+	Primitive interface {
+		redefine
+		MakeClosure(code, n any) (value, pure, alloc)
+		ClosureCode(c any) (value, pure)
+		ClosureRef(c, i any) (value, pure)
+		ClosureCodeSet(c, code any) effect
+		ClosureDataSet(c, i, x any) effect
+	},
 	Symbol inherit,
 	RecBinding inherit,
 
 	Expr interface {
 		Closures() omit
-		Labels(Bindings []RecBinding, Body Expr)
+		Labels(Bindings scope[[]RecBinding], Body scope[Expr])
 	},
 	LambdaExpr interface {
-		Lambda(Params []Symbol, Body Expr)
+		Lambda(Params bind[[]Symbol], Body scope[Expr])
 	},
 ] language
 
@@ -241,7 +312,8 @@ type L13[
 type L14[
 	RecBinding, Symbol inherit,
 	Program interface {
-		Labels(Bindings []RecBinding, Entry Symbol)
+		entry
+		Labels(Bindings scope[[]RecBinding], Entry scope[Symbol])
 	},
 ] language
 
@@ -253,7 +325,7 @@ type L15[
 	Const, Primitive, Symbol inherit,
 
 	Expr interface {
-		**Symbol // TODO(mdempsky): should be implicit
+		*Symbol | omit
 		*SimpleExpr
 		PrimCall(Prim Primitive, Args []SimpleExpr)
 		Apply(Fun SimpleExpr, Args []SimpleExpr)
@@ -271,9 +343,22 @@ type L15[
 // (effects) and expressions (values) and predicates that need to be
 // simply values.
 type L16[
-	Expr omit, // should be absent anyway; asserts this
-	Const, Symbol inherit,
-	ValuePrim, EffectPrim, PredicatePrim define,
+	Const, Primitive, Symbol inherit,
+	ValuePrim interface {
+		define
+		*Primitive
+		value
+	},
+	EffectPrim interface {
+		define
+		*Primitive
+		effect
+	},
+	PredicatePrim interface {
+		define
+		*Primitive
+		predicate
+	},
 
 	SimpleExpr interface {
 		*Symbol
@@ -281,14 +366,14 @@ type L16[
 		Quote(X Const)
 	},
 	Binding struct {
-		Var Symbol
+		Var bind[Symbol]
 		Val Value
 	},
 	Value interface {
 		*SimpleExpr
 		IfValue(Cond Predicate, Then, Else Value)
 		BeginValue(Init []Effect, X Value)
-		LetValue(Bindings []Binding, Body Value)
+		LetValue(Bindings []Binding, Body scope[Value])
 		PrimValue(Prim ValuePrim, Args []SimpleExpr)
 		ApplyValue(Fun SimpleExpr, Args []SimpleExpr)
 	},
@@ -296,7 +381,7 @@ type L16[
 		Nop()
 		IfEffect(Cond Predicate, Then, Else Effect)
 		BeginEffect(Init []Effect, X Effect) // TODO(mdempsky): Why non-empty? For consistency I guess?
-		LetEffect(Bindings []Binding, Body Effect)
+		LetEffect(Bindings []Binding, Body scope[Effect])
 		PrimEffect(Prim EffectPrim, Args []SimpleExpr)
 		ApplyEffect(Fun SimpleExpr, Args []SimpleExpr)
 	},
@@ -305,11 +390,11 @@ type L16[
 		False()
 		IfPred(Cond Predicate, Then, Else Predicate)
 		BeginPred(Init []Effect, X Predicate)
-		LetPred(Bindings []Binding, Body Predicate)
+		LetPred(Bindings []Binding, Body scope[Predicate])
 		PrimPred(Prim PredicatePrim, Args []SimpleExpr)
 	},
 	LambdaExpr interface {
-		Lambda(Params []Symbol, Body Value)
+		Lambda(Params bind[[]Symbol], Body scope[Value])
 	},
 ] language
 
@@ -319,7 +404,11 @@ type L16[
 // the alloc form.
 type L17[
 	SimpleExpr inherit,
-	ValuePrim, EffectPrim redefine,
+	ValuePrim interface {
+		redefine
+		alloc | omit
+	},
+	EffectPrim redefine,
 	Value interface {
 		Alloc(Tag int64, Size SimpleExpr)
 	},
@@ -342,7 +431,7 @@ type L18[
 		LetPred() omit
 	},
 	LambdaExpr interface {
-		Lambda(Params, Locals []Symbol, Body Value)
+		Lambda(Params, Locals bind[[]Symbol], Body scope[Value])
 	},
 ] language
 
@@ -353,7 +442,7 @@ type L19[
 	Symbol, ValuePrim inherit,
 	SimpleExpr inherit,
 	Value interface {
-		**SimpleExpr // TODO(mdempsky): again, should be implied
+		*SimpleExpr | omit
 		*Rhs
 	},
 	Rhs interface {
@@ -376,8 +465,6 @@ type L19[
 // L21 removes quoted constants and replace it with our raw ptr
 // representation (i.e. 64-bit integers)
 type L21[
-	// TODO(mdempsky): Const here is now unreachable.
-	Datum omit,
 	SimpleExpr interface {
 		Quote() omit
 		Int(Int int64)
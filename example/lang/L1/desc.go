@@ -0,0 +1,243 @@
+// Code generated by Hermes. DO NOT EDIT.
+
+package L1
+
+import (
+	"reflect"
+
+	"github.com/mdempsky/hermes/lang"
+)
+
+// Language describes L1.
+var Language = &lang.Language{
+	Name:  "L1",
+	Path:  "github.com/mdempsky/hermes/example/lang/L1",
+	Entry: "Expr",
+	Var:   "Symbol",
+	Meta:  "Meta",
+	Defs: []*lang.Def{
+		{
+			Name:   "Binding",
+			Kind:   lang.Product,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Binding](),
+			Fields: []lang.Field{
+				{Name: "Var", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}, Bind: true},
+				{Name: "Val", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+			},
+		},
+		{
+			Name:   "Const",
+			Kind:   lang.NonTerminal,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Const](),
+			IsAlso: []string{"Datum", "Expr"},
+			Cons: []*lang.Con{
+				{
+					Name:   "False",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[False](),
+				},
+				{
+					Name:   "Int",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Int](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.Int, Name: "int"}},
+					},
+				},
+				{
+					Name:   "Nil",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Nil](),
+				},
+				{
+					Name:   "True",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[True](),
+				},
+			},
+		},
+		{
+			Name:   "Datum",
+			Kind:   lang.NonTerminal,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Datum](),
+			Embeds: []string{"Const"},
+			Cons: []*lang.Con{
+				{
+					Name:   "Pair",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Pair](),
+					Fields: []lang.Field{
+						{Name: "Car", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Datum"}},
+						{Name: "Cdr", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Datum"}},
+					},
+				},
+				{
+					Name:   "Vector",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Vector](),
+					Fields: []lang.Field{
+						{Name: "List", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Datum"}}},
+					},
+				},
+			},
+		},
+		{
+			Name:   "Expr",
+			Kind:   lang.NonTerminal,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Expr](),
+			Embeds: []string{"Const", "Primitive", "Symbol"},
+			Cons: []*lang.Con{
+				{
+					Name:   "And",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[And](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}}},
+					},
+				},
+				{
+					Name:   "Apply",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Apply](),
+					Fields: []lang.Field{
+						{Name: "Fun", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+						{Name: "Args", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}}},
+					},
+				},
+				{
+					Name:   "Begin",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Begin](),
+					Fields: []lang.Field{
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}}},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+					},
+				},
+				{
+					Name:   "If",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[If](),
+					Fields: []lang.Field{
+						{Name: "Cond", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+						{Name: "Then", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+						{Name: "Else", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+					},
+				},
+				{
+					Name:   "Lambda",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Lambda](),
+					Fields: []lang.Field{
+						{Name: "Params", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}}, Bind: true},
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}}, Scope: true},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}, Scope: true},
+					},
+				},
+				{
+					Name:   "Let",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Let](),
+					Fields: []lang.Field{
+						{Name: "Bindings", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Product, Name: "Binding"}}},
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}}, Scope: true},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}, Scope: true},
+					},
+				},
+				{
+					Name:   "LetRec",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[LetRec](),
+					Fields: []lang.Field{
+						{Name: "Bindings", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Product, Name: "Binding"}}, Scope: true},
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}}, Scope: true},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}, Scope: true},
+					},
+				},
+				{
+					Name:   "Not",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Not](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+					},
+				},
+				{
+					Name:   "Or",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Or](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}}},
+					},
+				},
+				{
+					Name:   "Quote",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Quote](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Datum"}},
+					},
+				},
+				{
+					Name:   "Set",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Set](),
+					Fields: []lang.Field{
+						{Name: "Var", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}},
+						{Name: "Val", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+					},
+				},
+			},
+		},
+		{
+			Name:   "Primitive",
+			Kind:   lang.Terminal,
+			From:   "L1",
+			GoType: reflect.TypeFor[Primitive](),
+			IsAlso: []string{"Expr"},
+			Prims: []*lang.Prim{
+				primitiveTable[PrimitiveAdd],
+				primitiveTable[PrimitiveSub],
+				primitiveTable[PrimitiveMul],
+				primitiveTable[PrimitiveDiv],
+				primitiveTable[PrimitiveCar],
+				primitiveTable[PrimitiveCdr],
+				primitiveTable[PrimitiveCons],
+				primitiveTable[PrimitiveMakeVector],
+				primitiveTable[PrimitiveVectorRef],
+				primitiveTable[PrimitiveVectorLength],
+				primitiveTable[PrimitiveBox],
+				primitiveTable[PrimitiveUnbox],
+				primitiveTable[PrimitiveSetCar],
+				primitiveTable[PrimitiveSetCdr],
+				primitiveTable[PrimitiveVectorSet],
+				primitiveTable[PrimitiveSetBox],
+				primitiveTable[PrimitiveLt],
+				primitiveTable[PrimitiveLe],
+				primitiveTable[PrimitiveNumEq],
+				primitiveTable[PrimitiveGe],
+				primitiveTable[PrimitiveGt],
+				primitiveTable[PrimitiveEq],
+				primitiveTable[PrimitiveIsBoolean],
+				primitiveTable[PrimitiveIsBox],
+				primitiveTable[PrimitiveIsNull],
+				primitiveTable[PrimitiveIsPair],
+				primitiveTable[PrimitiveIsProcedure],
+				primitiveTable[PrimitiveIsVector],
+				primitiveTable[PrimitiveVoid],
+			},
+		},
+		{
+			Name:   "Symbol",
+			Kind:   lang.Terminal,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Symbol](),
+			IsAlso: []string{"Expr"},
+		},
+	},
+}
+
+func init() { lang.Register(Language) }
@@ -0,0 +1,59 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L1
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	x, y := Symbol("x"), Symbol("y")
+	tests := []struct {
+		node Node
+		want string
+	}{
+		{Int{X: 7}, "7"},
+		{True{}, "(true)"},
+		{Binding{Var: x, Val: Int{X: 1}}, "[x 1]"},
+		{If{Cond: x, Then: Int{X: 1}, Else: Int{X: 2}}, "(if x 1 2)"},
+		{Not{X: x}, "(not x)"},
+		{And{X: []Expr{x, y}}, "(and x y)"},
+		{Begin{Init: []Expr{x}, Body: y}, "(begin (x) y)"},
+		{Set{Var: x, Val: y}, "(set x y)"},
+		{Quote{X: Pair{Car: Int{X: 1}, Cdr: Nil{}}}, "(quote (pair 1 (nil)))"},
+		{Quote{X: Vector{List: []Datum{Int{X: 1}, True{}}}}, "(quote (vector 1 (true)))"},
+		{
+			Let{Bindings: []Binding{{Var: x, Val: Int{X: 1}}}, Body: x},
+			"(let ([x 1]) () x)",
+		},
+		{
+			Lambda{Params: []Symbol{x, y}, Init: []Expr{Set{Var: x, Val: y}}, Body: x},
+			"(lambda (x y) ((set x y)) x)",
+		},
+		{
+			Apply{Fun: PrimitiveCons, Args: []Expr{x, y}},
+			"(cons x y)",
+		},
+		{
+			LetRec{
+				Bindings: []Binding{
+					{Var: "even?", Val: Lambda{Params: []Symbol{"n"}, Body: If{Cond: Apply{Fun: Symbol("zero?"), Args: []Expr{Symbol("n")}}, Then: True{}, Else: Apply{Fun: Symbol("odd?"), Args: []Expr{Apply{Fun: Symbol("sub1"), Args: []Expr{Symbol("n")}}}}}}},
+					{Var: "odd?", Val: Lambda{Params: []Symbol{"n"}, Body: If{Cond: Apply{Fun: Symbol("zero?"), Args: []Expr{Symbol("n")}}, Then: False{}, Else: Apply{Fun: Symbol("even?"), Args: []Expr{Apply{Fun: Symbol("sub1"), Args: []Expr{Symbol("n")}}}}}}},
+				},
+				Body: Apply{Fun: Symbol("even?"), Args: []Expr{Int{X: 10}}},
+			},
+			`(letrec ([even? (lambda (n) () (if (zero? n) (true) (odd? (sub1 n))))]
+         [odd? (lambda (n) () (if (zero? n) (false) (even? (sub1 n))))])
+  ()
+  (even? 10))`,
+		},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.node); got != tt.want {
+			t.Errorf("Format(%#v) =\n%s\nwant\n%s", tt.node, got, tt.want)
+		}
+		if got := tt.node.(interface{ String() string }).String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.node, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Code generated by Hermes. DO NOT EDIT.
+
+package L11
+
+import (
+	"reflect"
+
+	"github.com/mdempsky/hermes/lang"
+)
+
+// Language describes L11.
+var Language = &lang.Language{
+	Name:  "L11",
+	Path:  "github.com/mdempsky/hermes/example/lang/L11",
+	Entry: "Expr",
+	Var:   "Symbol",
+	Meta:  "Meta",
+	Defs: []*lang.Def{
+		{
+			Name:   "Binding",
+			Kind:   lang.Product,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Binding](),
+			Fields: []lang.Field{
+				{Name: "Var", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}, Bind: true},
+				{Name: "Val", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+			},
+		},
+		{
+			Name:   "Const",
+			Kind:   lang.NonTerminal,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Const](),
+			Cons: []*lang.Con{
+				{
+					Name:   "False",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[False](),
+				},
+				{
+					Name:   "Int",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Int](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.Int, Name: "int"}},
+					},
+				},
+				{
+					Name:   "Nil",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Nil](),
+				},
+				{
+					Name:   "True",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[True](),
+				},
+			},
+		},
+		{
+			Name:   "Expr",
+			Kind:   lang.NonTerminal,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Expr](),
+			Embeds: []string{"Symbol"},
+			Cons: []*lang.Con{
+				{
+					Name:   "Apply",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Apply](),
+					Fields: []lang.Field{
+						{Name: "Fun", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+						{Name: "Args", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}}},
+					},
+				},
+				{
+					Name:   "Begin",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Begin](),
+					Fields: []lang.Field{
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}}},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+					},
+				},
+				{
+					Name:   "If",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[If](),
+					Fields: []lang.Field{
+						{Name: "Cond", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+						{Name: "Then", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+						{Name: "Else", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+					},
+				},
+				{
+					Name:   "Let",
+					From:   "L10",
+					GoType: reflect.TypeFor[Let](),
+					Fields: []lang.Field{
+						{Name: "Bindings", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Product, Name: "Binding"}}},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}, Scope: true},
+					},
+				},
+				{
+					Name:   "LetRec",
+					From:   "L8",
+					GoType: reflect.TypeFor[LetRec](),
+					Fields: []lang.Field{
+						{Name: "Bindings", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Product, Name: "RecBinding"}}, Scope: true},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}, Scope: true},
+					},
+				},
+				{
+					Name:   "PrimCall",
+					From:   "L4",
+					GoType: reflect.TypeFor[PrimCall](),
+					Fields: []lang.Field{
+						{Name: "Prim", Type: &lang.Type{Kind: lang.Terminal, Name: "Primitive"}},
+						{Name: "Args", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}}},
+					},
+				},
+				{
+					Name:   "Quote",
+					From:   "L6",
+					GoType: reflect.TypeFor[Quote](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Const"}},
+					},
+				},
+			},
+		},
+		{
+			Name:   "FreeBody",
+			Kind:   lang.NonTerminal,
+			From:   "L11",
+			GoType: reflect.TypeFor[FreeBody](),
+			Cons: []*lang.Con{
+				{
+					Name:   "Free",
+					From:   "L11",
+					GoType: reflect.TypeFor[Free](),
+					Fields: []lang.Field{
+						{Name: "Free", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}}},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Expr"}},
+					},
+				},
+			},
+		},
+		{
+			Name:   "LambdaExpr",
+			Kind:   lang.NonTerminal,
+			From:   "L8",
+			GoType: reflect.TypeFor[LambdaExpr](),
+			Cons: []*lang.Con{
+				{
+					Name:   "Lambda",
+					From:   "L11",
+					GoType: reflect.TypeFor[Lambda](),
+					Fields: []lang.Field{
+						{Name: "Params", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}}, Bind: true},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "FreeBody"}, Scope: true},
+					},
+				},
+			},
+		},
+		{
+			Name:   "Primitive",
+			Kind:   lang.Terminal,
+			From:   "L1",
+			GoType: reflect.TypeFor[Primitive](),
+			Prims: []*lang.Prim{
+				primitiveTable[PrimitiveAdd],
+				primitiveTable[PrimitiveSub],
+				primitiveTable[PrimitiveMul],
+				primitiveTable[PrimitiveDiv],
+				primitiveTable[PrimitiveCar],
+				primitiveTable[PrimitiveCdr],
+				primitiveTable[PrimitiveCons],
+				primitiveTable[PrimitiveMakeVector],
+				primitiveTable[PrimitiveVectorRef],
+				primitiveTable[PrimitiveVectorLength],
+				primitiveTable[PrimitiveBox],
+				primitiveTable[PrimitiveUnbox],
+				primitiveTable[PrimitiveSetCar],
+				primitiveTable[PrimitiveSetCdr],
+				primitiveTable[PrimitiveVectorSet],
+				primitiveTable[PrimitiveSetBox],
+				primitiveTable[PrimitiveLt],
+				primitiveTable[PrimitiveLe],
+				primitiveTable[PrimitiveNumEq],
+				primitiveTable[PrimitiveGe],
+				primitiveTable[PrimitiveGt],
+				primitiveTable[PrimitiveEq],
+				primitiveTable[PrimitiveIsBoolean],
+				primitiveTable[PrimitiveIsBox],
+				primitiveTable[PrimitiveIsNull],
+				primitiveTable[PrimitiveIsPair],
+				primitiveTable[PrimitiveIsProcedure],
+				primitiveTable[PrimitiveIsVector],
+				primitiveTable[PrimitiveVoid],
+			},
+		},
+		{
+			Name:   "RecBinding",
+			Kind:   lang.Product,
+			From:   "L8",
+			GoType: reflect.TypeFor[RecBinding](),
+			Fields: []lang.Field{
+				{Name: "Var", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}, Bind: true},
+				{Name: "Val", Type: &lang.Type{Kind: lang.NonTerminal, Name: "LambdaExpr"}},
+			},
+		},
+		{
+			Name:   "Symbol",
+			Kind:   lang.Terminal,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Symbol](),
+			IsAlso: []string{"Expr"},
+		},
+	},
+}
+
+func init() { lang.Register(Language) }
@@ -0,0 +1,145 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L12
+
+import (
+	"slices"
+	"testing"
+)
+
+func mustParse(t *testing.T, src string) Expr {
+	t.Helper()
+	x, err := ParseExpr(src)
+	if err != nil {
+		t.Fatalf("ParseExpr(%q): %v", src, err)
+	}
+	return x
+}
+
+func TestFreeVars(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []Symbol
+	}{
+		{"x", []Symbol{"x"}},
+		{"(f x f)", []Symbol{"f", "x"}},
+		{"(let ([x y]) (x z))", []Symbol{"y", "z"}},
+		{"(let ([x x]) x)", []Symbol{"x"}},
+		{"(label l)", []Symbol{"l"}},
+		{"(closures ([f l c]) (labels ([l (lambda (cp z) (free (c) (c z)))]) (f (label l))))", []Symbol{"c"}},
+		{"(closures ([f l f]) (labels ([l (lambda (cp) (free (f) (f g)))]) f))", []Symbol{"g"}},
+	}
+	for _, tt := range tests {
+		if got := FreeVars(mustParse(t, tt.src)); !slices.Equal(got, tt.want) {
+			t.Errorf("FreeVars(%v) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+
+	// A labels form binds its labels by itself, but not the closures
+	// of the form that contains it.
+	x := mustParse(t, "(closures ([f l c]) (labels ([l (lambda (cp) (free (c) c))]) (f (label l))))").(Closures)
+	if got, want := FreeVars(x.Body), []Symbol{"c", "f"}; !slices.Equal(got, want) {
+		t.Errorf("FreeVars(%v) = %v, want %v", Format(x.Body), got, want)
+	}
+}
+
+func TestSubst(t *testing.T) {
+	tests := []struct {
+		src  string
+		s    map[Symbol]Node
+		want string
+	}{
+		{"(let ([x y]) (x y))", map[Symbol]Node{"y": Symbol("z")}, "(let ([x z]) (x z))"},
+		{"(let ([y y]) y)", map[Symbol]Node{"y": Symbol("z")}, "(let ([y z]) y)"},
+		{"(f x)", map[Symbol]Node{"x": Quote{X: Int{X: 1}}}, "(f (quote 1))"},
+		{"(let ([z (quote 1)]) (y z))", map[Symbol]Node{"y": Symbol("z")}, "(let ([z.1 (quote 1)]) (z z.1))"},
+		{
+			"(closures ([f l x]) (labels ([l (lambda (cp) (free (x) x))]) (f y)))",
+			map[Symbol]Node{"y": Symbol("f"), "x": Symbol("w")},
+			"(closures ([f.1 l w]) (labels ([l (lambda (cp) (free (w) w))]) (f.1 f)))",
+		},
+	}
+	for _, tt := range tests {
+		x := mustParse(t, tt.src)
+		got := Subst(x, tt.s)
+		if want := mustParse(t, tt.want); !Equal(got, want) {
+			t.Errorf("Subst(%v, %v) = %v, want %v", tt.src, tt.s, Format(got), tt.want)
+		}
+		if !Equal(x, mustParse(t, tt.src)) {
+			t.Errorf("Subst(%v, %v) modified its argument", tt.src, tt.s)
+		}
+	}
+}
+
+func TestSubstPanics(t *testing.T) {
+	x := mustParse(t, "(label x)")
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Subst of an Apply for the Symbol in %v did not panic", Format(x))
+		}
+	}()
+	Subst(x, map[Symbol]Node{"x": Apply{Fun: Symbol("f")}})
+}
+
+func TestAlphaEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"(let ([x (quote 1)]) x)", "(let ([y (quote 1)]) y)", true},
+		{"(let ([x (quote 1)]) z)", "(let ([y (quote 1)]) z)", true},
+		{"(let ([x (quote 1)]) x)", "(let ([y (quote 1)]) x)", false},
+		{"(f x)", "(f y)", false},
+		{"(let ([x (quote 1)] [y (quote 2)]) x)", "(let ([y (quote 1)] [x (quote 2)]) y)", true},
+		{"(let ([x (quote 1)] [y (quote 2)]) x)", "(let ([y (quote 1)] [x (quote 2)]) x)", false},
+		{"(let ([x (quote 1)]) x)", "(let ([x (quote 2)]) x)", false},
+		{
+			"(closures ([f l x]) (labels ([l (lambda (cp y) (free (x) (y x)))]) (f (label l))))",
+			"(closures ([g m x]) (labels ([m (lambda (q z) (free (x) (z x)))]) (g (label m))))",
+			true,
+		},
+		{
+			"(closures ([f l x]) (labels ([l (lambda (cp) (free (x) x))]) (f (label l))))",
+			"(closures ([f l y]) (labels ([l (lambda (cp) (free (y) y))]) (f (label l))))",
+			false,
+		},
+	}
+	for _, tt := range tests {
+		if got := AlphaEqual(mustParse(t, tt.a), mustParse(t, tt.b)); got != tt.want {
+			t.Errorf("AlphaEqual(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestUniquify(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"(let ([x (quote 1)]) (let ([y x]) y))", "(let ([x (quote 1)]) (let ([y x]) y))"},
+		{"(let ([x (quote 1)]) (let ([x x]) x))", "(let ([x (quote 1)]) (let ([x.1 x]) x.1))"},
+		{"(let ([x x]) x)", "(let ([x.1 x]) x.1)"},
+		{
+			// Renaming a label renames the closure's reference to it.
+			"(let ([l (quote 1)]) (closures ([f l x]) (labels ([l (lambda (cp) (free (x) x))]) (f (label l)))))",
+			"(let ([l (quote 1)]) (closures ([f l.1 x]) (labels ([l.1 (lambda (cp) (free (x) x))]) (f (label l.1)))))",
+		},
+		{
+			// Renaming a closure renames the free variables that refer
+			// to it.
+			"(let ([f (quote 1)]) (closures ([f l f y]) (labels ([l (lambda (cp) (free (f y) (f y)))]) f)))",
+			"(let ([f (quote 1)]) (closures ([f.1 l f.1 y]) (labels ([l (lambda (cp) (free (f.1 y) (f.1 y)))]) f.1)))",
+		},
+	}
+	for _, tt := range tests {
+		x := mustParse(t, tt.src)
+		got := Uniquify(x)
+		if want := mustParse(t, tt.want); !Equal(got, want) {
+			t.Errorf("Uniquify(%v) = %v, want %v", tt.src, Format(got), tt.want)
+		}
+		if !AlphaEqual(got, x) {
+			t.Errorf("Uniquify(%v) = %v, which isn't alpha-equivalent", tt.src, Format(got))
+		}
+	}
+}
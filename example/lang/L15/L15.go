@@ -2,113 +2,259 @@
 
 package L15
 
+import (
+	"strconv"
+
+	"github.com/mdempsky/hermes/example/term"
+	"github.com/mdempsky/hermes/lang"
+)
+
 type terminal int
 
+// Entry is the entry non-terminal of L15.
+type Entry = Program
+
+// A Node is a terminal, product type, or production value.
+type Node interface{ isNode() }
+
 type (
 	Binding struct {
-		Var Symbol
-		Val Expr
-	}
-	Closure struct {
-		X Symbol
-		L Symbol
-		F []Symbol
+		Var  Symbol
+		Val  Expr
+		Meta lang.Meta[any]
 	}
 	Primitive  terminal // from L13
 	RecBinding struct {
-		Var Symbol
-		Val LambdaExpr
+		Var  Symbol
+		Val  LambdaExpr
+		Meta lang.Meta[any]
 	}
-	Symbol terminal // from Lsrc
+	Symbol term.Symbol // from Lsrc
 )
 
 type (
 	Const interface {
-		Datum
+		Node
 		isConst()
 	}
-	False struct{}
-	Int   struct{ X int }
-	Nil   struct{}
-	True  struct{}
-)
-
-type (
-	Datum  interface{ isDatum() }
-	Pair   struct{ Car, Cdr Datum }
-	Vector struct{ List []Datum }
+	False struct{ Meta lang.Meta[any] }
+	Int   struct {
+		X    int
+		Meta lang.Meta[any]
+	}
+	Nil  struct{ Meta lang.Meta[any] }
+	True struct{ Meta lang.Meta[any] }
 )
 
 type (
-	Expr  interface{ isExpr() }
+	Expr interface {
+		Node
+		isExpr()
+	}
 	Apply struct {
 		Fun  SimpleExpr
 		Args []SimpleExpr
+		Meta lang.Meta[any]
 	}
 	Begin struct {
 		Init []Expr
 		Body Expr
+		Meta lang.Meta[any]
+	}
+	If struct {
+		Cond, Then, Else Expr
+		Meta             lang.Meta[any]
 	}
-	If  struct{ Cond, Then, Else Expr }
 	Let struct {
 		Bindings []Binding
 		Body     Expr
+		Meta     lang.Meta[any]
 	}
 	PrimCall struct {
 		Prim Primitive
 		Args []SimpleExpr
+		Meta lang.Meta[any]
 	}
 )
 
 type (
-	LabelsBody interface{ isLabelsBody() }
-)
-
-type (
-	LambdaExpr interface{ isLambdaExpr() }
-	Lambda     struct {
+	LambdaExpr interface {
+		Node
+		isLambdaExpr()
+	}
+	Lambda struct {
 		Params []Symbol
 		Body   Expr
+		Meta   lang.Meta[any]
 	}
 )
 
 type (
-	Program interface{ isProgram() }
-	Labels  struct {
+	Program interface {
+		Node
+		isProgram()
+	}
+	Labels struct {
 		Bindings []RecBinding
 		Entry    Symbol
+		Meta     lang.Meta[any]
 	}
 )
 
 type (
 	SimpleExpr interface {
+		Node
 		Expr
 		isSimpleExpr()
 	}
-	Label struct{ Name Symbol }
-	Quote struct{ X Const }
+	Label struct {
+		Name Symbol
+		Meta lang.Meta[any]
+	}
+	Quote struct {
+		X    Const
+		Meta lang.Meta[any]
+	}
 )
 
+func (Binding) isNode()      {}
+func (False) isNode()        {}
 func (False) isConst()       {}
-func (False) isDatum()       {}
+func (Int) isNode()          {}
 func (Int) isConst()         {}
-func (Int) isDatum()         {}
+func (Nil) isNode()          {}
 func (Nil) isConst()         {}
-func (Nil) isDatum()         {}
+func (True) isNode()         {}
 func (True) isConst()        {}
-func (True) isDatum()        {}
-func (Pair) isDatum()        {}
-func (Vector) isDatum()      {}
+func (Apply) isNode()        {}
 func (Apply) isExpr()        {}
+func (Begin) isNode()        {}
 func (Begin) isExpr()        {}
+func (If) isNode()           {}
 func (If) isExpr()           {}
+func (Let) isNode()          {}
 func (Let) isExpr()          {}
+func (PrimCall) isNode()     {}
 func (PrimCall) isExpr()     {}
+func (Lambda) isNode()       {}
 func (Lambda) isLambdaExpr() {}
+func (Primitive) isNode()    {}
+func (Labels) isNode()       {}
 func (Labels) isProgram()    {}
+func (RecBinding) isNode()   {}
+func (Label) isNode()        {}
 func (Label) isExpr()        {}
 func (Label) isSimpleExpr()  {}
+func (Quote) isNode()        {}
 func (Quote) isExpr()        {}
 func (Quote) isSimpleExpr()  {}
+func (Symbol) isNode()       {}
 func (Symbol) isExpr()       {}
 func (Symbol) isSimpleExpr() {}
+
+// String returns the text of x, as written by Unparse.
+func (x Symbol) String() string { return string(x) }
+
+// Valid reports whether x is a valid Symbol.
+func (x Symbol) Valid() bool { return term.Symbol(x).Valid() }
+
+// The primitives of Primitive.
+const (
+	PrimitiveAdd            Primitive = 1  // add
+	PrimitiveSub            Primitive = 2  // sub
+	PrimitiveMul            Primitive = 3  // mul
+	PrimitiveDiv            Primitive = 4  // div
+	PrimitiveCar            Primitive = 5  // car
+	PrimitiveCdr            Primitive = 6  // cdr
+	PrimitiveCons           Primitive = 7  // cons
+	PrimitiveMakeVector     Primitive = 8  // make-vector
+	PrimitiveVectorRef      Primitive = 9  // vector-ref
+	PrimitiveVectorLength   Primitive = 10 // vector-length
+	PrimitiveBox            Primitive = 11 // box
+	PrimitiveUnbox          Primitive = 12 // unbox
+	PrimitiveSetCar         Primitive = 13 // set-car
+	PrimitiveSetCdr         Primitive = 14 // set-cdr
+	PrimitiveVectorSet      Primitive = 15 // vector-set
+	PrimitiveSetBox         Primitive = 16 // set-box
+	PrimitiveLt             Primitive = 17 // lt
+	PrimitiveLe             Primitive = 18 // le
+	PrimitiveNumEq          Primitive = 19 // num-eq
+	PrimitiveGe             Primitive = 20 // ge
+	PrimitiveGt             Primitive = 21 // gt
+	PrimitiveEq             Primitive = 22 // eq
+	PrimitiveIsBoolean      Primitive = 23 // is-boolean
+	PrimitiveIsBox          Primitive = 24 // is-box
+	PrimitiveIsNull         Primitive = 25 // is-null
+	PrimitiveIsPair         Primitive = 26 // is-pair
+	PrimitiveIsProcedure    Primitive = 27 // is-procedure
+	PrimitiveIsVector       Primitive = 28 // is-vector
+	PrimitiveVoid           Primitive = 29 // void
+	PrimitiveMakeClosure    Primitive = 30 // make-closure
+	PrimitiveClosureCode    Primitive = 31 // closure-code
+	PrimitiveClosureRef     Primitive = 32 // closure-ref
+	PrimitiveClosureCodeSet Primitive = 33 // closure-code-set
+	PrimitiveClosureDataSet Primitive = 34 // closure-data-set
+)
+
+// Prim returns the description of x, or nil if x is not one of
+// Primitive's primitives.
+func (x Primitive) Prim() *lang.Prim { return primitiveTable[x] }
+
+// String returns the name of x, as written by Unparse.
+func (x Primitive) String() string {
+	if p := x.Prim(); p != nil {
+		return p.Name
+	}
+	return "Primitive(" + strconv.Itoa(int(x)) + ")"
+}
+
+// Valid reports whether x is one of Primitive's primitives.
+func (x Primitive) Valid() bool { return x.Prim() != nil }
+
+// LookupPrimitive returns the primitive of Primitive with the given
+// name, if any.
+func LookupPrimitive(name string) (Primitive, bool) {
+	for x, p := range primitiveTable {
+		if p.Name == name {
+			return x, true
+		}
+	}
+	return 0, false
+}
+
+var primitiveTable = map[Primitive]*lang.Prim{
+	PrimitiveAdd:            {Name: "add", Code: 1, Arity: 2, Class: lang.Value, Pure: true},
+	PrimitiveSub:            {Name: "sub", Code: 2, Arity: 2, Class: lang.Value, Pure: true},
+	PrimitiveMul:            {Name: "mul", Code: 3, Arity: 2, Class: lang.Value, Pure: true},
+	PrimitiveDiv:            {Name: "div", Code: 4, Arity: 2, Class: lang.Value, Pure: true},
+	PrimitiveCar:            {Name: "car", Code: 5, Arity: 1, Class: lang.Value, Pure: true},
+	PrimitiveCdr:            {Name: "cdr", Code: 6, Arity: 1, Class: lang.Value, Pure: true},
+	PrimitiveCons:           {Name: "cons", Code: 7, Arity: 2, Class: lang.Value, Pure: true, Alloc: true},
+	PrimitiveMakeVector:     {Name: "make-vector", Code: 8, Arity: 1, Class: lang.Value, Pure: true, Alloc: true},
+	PrimitiveVectorRef:      {Name: "vector-ref", Code: 9, Arity: 2, Class: lang.Value, Pure: true},
+	PrimitiveVectorLength:   {Name: "vector-length", Code: 10, Arity: 1, Class: lang.Value, Pure: true},
+	PrimitiveBox:            {Name: "box", Code: 11, Arity: 1, Class: lang.Value, Pure: true, Alloc: true},
+	PrimitiveUnbox:          {Name: "unbox", Code: 12, Arity: 1, Class: lang.Value, Pure: true},
+	PrimitiveSetCar:         {Name: "set-car", Code: 13, Arity: 2, Class: lang.Effect},
+	PrimitiveSetCdr:         {Name: "set-cdr", Code: 14, Arity: 2, Class: lang.Effect},
+	PrimitiveVectorSet:      {Name: "vector-set", Code: 15, Arity: 3, Class: lang.Effect},
+	PrimitiveSetBox:         {Name: "set-box", Code: 16, Arity: 2, Class: lang.Effect},
+	PrimitiveLt:             {Name: "lt", Code: 17, Arity: 2, Class: lang.Predicate, Pure: true},
+	PrimitiveLe:             {Name: "le", Code: 18, Arity: 2, Class: lang.Predicate, Pure: true},
+	PrimitiveNumEq:          {Name: "num-eq", Code: 19, Arity: 2, Class: lang.Predicate, Pure: true},
+	PrimitiveGe:             {Name: "ge", Code: 20, Arity: 2, Class: lang.Predicate, Pure: true},
+	PrimitiveGt:             {Name: "gt", Code: 21, Arity: 2, Class: lang.Predicate, Pure: true},
+	PrimitiveEq:             {Name: "eq", Code: 22, Arity: 2, Class: lang.Predicate, Pure: true},
+	PrimitiveIsBoolean:      {Name: "is-boolean", Code: 23, Arity: 1, Class: lang.Predicate, Pure: true},
+	PrimitiveIsBox:          {Name: "is-box", Code: 24, Arity: 1, Class: lang.Predicate, Pure: true},
+	PrimitiveIsNull:         {Name: "is-null", Code: 25, Arity: 1, Class: lang.Predicate, Pure: true},
+	PrimitiveIsPair:         {Name: "is-pair", Code: 26, Arity: 1, Class: lang.Predicate, Pure: true},
+	PrimitiveIsProcedure:    {Name: "is-procedure", Code: 27, Arity: 1, Class: lang.Predicate, Pure: true},
+	PrimitiveIsVector:       {Name: "is-vector", Code: 28, Arity: 1, Class: lang.Predicate, Pure: true},
+	PrimitiveVoid:           {Name: "void", Code: 29, Arity: 0, Class: lang.Value, Pure: true},
+	PrimitiveMakeClosure:    {Name: "make-closure", Code: 30, Arity: 2, Class: lang.Value, Pure: true, Alloc: true},
+	PrimitiveClosureCode:    {Name: "closure-code", Code: 31, Arity: 1, Class: lang.Value, Pure: true},
+	PrimitiveClosureRef:     {Name: "closure-ref", Code: 32, Arity: 2, Class: lang.Value, Pure: true},
+	PrimitiveClosureCodeSet: {Name: "closure-code-set", Code: 33, Arity: 2, Class: lang.Effect},
+	PrimitiveClosureDataSet: {Name: "closure-data-set", Code: 34, Arity: 3, Class: lang.Effect},
+}
@@ -2,169 +2,409 @@
 
 package L16
 
+import (
+	"strconv"
+
+	"github.com/mdempsky/hermes/example/term"
+	"github.com/mdempsky/hermes/lang"
+)
+
 type terminal int
 
+// Entry is the entry non-terminal of L16.
+type Entry = Program
+
+// A Node is a terminal, product type, or production value.
+type Node interface{ isNode() }
+
 type (
 	Binding struct {
-		Var Symbol
-		Val Value
-	}
-	Closure struct {
-		X Symbol
-		L Symbol
-		F []Symbol
+		Var  Symbol
+		Val  Value
+		Meta lang.Meta[any]
 	}
 	EffectPrim    terminal // from L16
 	PredicatePrim terminal // from L16
-	Primitive     terminal // from L13
 	RecBinding    struct {
-		Var Symbol
-		Val LambdaExpr
+		Var  Symbol
+		Val  LambdaExpr
+		Meta lang.Meta[any]
 	}
-	Symbol    terminal // from Lsrc
-	ValuePrim terminal // from L16
+	Symbol    term.Symbol // from Lsrc
+	ValuePrim terminal    // from L16
 )
 
 type (
 	Const interface {
-		Datum
+		Node
 		isConst()
 	}
-	Int struct{ X int }
-	Nil struct{}
-)
-
-type (
-	Datum  interface{ isDatum() }
-	Pair   struct{ Car, Cdr Datum }
-	Vector struct{ List []Datum }
+	Int struct {
+		X    int
+		Meta lang.Meta[any]
+	}
+	Nil struct{ Meta lang.Meta[any] }
 )
 
 type (
-	Effect      interface{ isEffect() }
+	Effect interface {
+		Node
+		isEffect()
+	}
 	ApplyEffect struct {
 		Fun  SimpleExpr
 		Args []SimpleExpr
+		Meta lang.Meta[any]
 	}
 	BeginEffect struct {
 		Init []Effect
 		X    Effect
+		Meta lang.Meta[any]
 	}
 	IfEffect struct {
 		Cond       Predicate
 		Then, Else Effect
+		Meta       lang.Meta[any]
 	}
 	LetEffect struct {
 		Bindings []Binding
 		Body     Effect
+		Meta     lang.Meta[any]
 	}
-	Nop        struct{}
+	Nop        struct{ Meta lang.Meta[any] }
 	PrimEffect struct {
 		Prim EffectPrim
 		Args []SimpleExpr
+		Meta lang.Meta[any]
 	}
 )
 
 type (
-	LabelsBody interface{ isLabelsBody() }
-)
-
-type (
-	LambdaExpr interface{ isLambdaExpr() }
-	Lambda     struct {
+	LambdaExpr interface {
+		Node
+		isLambdaExpr()
+	}
+	Lambda struct {
 		Params []Symbol
 		Body   Value
+		Meta   lang.Meta[any]
 	}
 )
 
 type (
-	Predicate interface{ isPredicate() }
+	Predicate interface {
+		Node
+		isPredicate()
+	}
 	BeginPred struct {
 		Init []Effect
 		X    Predicate
+		Meta lang.Meta[any]
+	}
+	False  struct{ Meta lang.Meta[any] }
+	IfPred struct {
+		Cond, Then, Else Predicate
+		Meta             lang.Meta[any]
 	}
-	False   struct{}
-	IfPred  struct{ Cond, Then, Else Predicate }
 	LetPred struct {
 		Bindings []Binding
 		Body     Predicate
+		Meta     lang.Meta[any]
 	}
 	PrimPred struct {
 		Prim PredicatePrim
 		Args []SimpleExpr
+		Meta lang.Meta[any]
 	}
-	True struct{}
+	True struct{ Meta lang.Meta[any] }
 )
 
 type (
-	Program interface{ isProgram() }
-	Labels  struct {
+	Program interface {
+		Node
+		isProgram()
+	}
+	Labels struct {
 		Bindings []RecBinding
 		Entry    Symbol
+		Meta     lang.Meta[any]
 	}
 )
 
 type (
 	SimpleExpr interface {
+		Node
 		Value
 		isSimpleExpr()
 	}
-	Label struct{ Name Symbol }
-	Quote struct{ X Const }
+	Label struct {
+		Name Symbol
+		Meta lang.Meta[any]
+	}
+	Quote struct {
+		X    Const
+		Meta lang.Meta[any]
+	}
 )
 
 type (
-	Value      interface{ isValue() }
+	Value interface {
+		Node
+		isValue()
+	}
 	ApplyValue struct {
 		Fun  SimpleExpr
 		Args []SimpleExpr
+		Meta lang.Meta[any]
 	}
 	BeginValue struct {
 		Init []Effect
 		X    Value
+		Meta lang.Meta[any]
 	}
 	IfValue struct {
 		Cond       Predicate
 		Then, Else Value
+		Meta       lang.Meta[any]
 	}
 	LetValue struct {
 		Bindings []Binding
 		Body     Value
+		Meta     lang.Meta[any]
 	}
 	PrimValue struct {
 		Prim ValuePrim
 		Args []SimpleExpr
+		Meta lang.Meta[any]
 	}
 )
 
+func (Binding) isNode()        {}
+func (Int) isNode()            {}
 func (Int) isConst()           {}
-func (Int) isDatum()           {}
+func (Nil) isNode()            {}
 func (Nil) isConst()           {}
-func (Nil) isDatum()           {}
-func (Pair) isDatum()          {}
-func (Vector) isDatum()        {}
+func (ApplyEffect) isNode()    {}
 func (ApplyEffect) isEffect()  {}
+func (BeginEffect) isNode()    {}
 func (BeginEffect) isEffect()  {}
+func (IfEffect) isNode()       {}
 func (IfEffect) isEffect()     {}
+func (LetEffect) isNode()      {}
 func (LetEffect) isEffect()    {}
+func (Nop) isNode()            {}
 func (Nop) isEffect()          {}
+func (PrimEffect) isNode()     {}
 func (PrimEffect) isEffect()   {}
+func (EffectPrim) isNode()     {}
+func (Lambda) isNode()         {}
 func (Lambda) isLambdaExpr()   {}
+func (BeginPred) isNode()      {}
 func (BeginPred) isPredicate() {}
+func (False) isNode()          {}
 func (False) isPredicate()     {}
+func (IfPred) isNode()         {}
 func (IfPred) isPredicate()    {}
+func (LetPred) isNode()        {}
 func (LetPred) isPredicate()   {}
+func (PrimPred) isNode()       {}
 func (PrimPred) isPredicate()  {}
+func (True) isNode()           {}
 func (True) isPredicate()      {}
+func (PredicatePrim) isNode()  {}
+func (Labels) isNode()         {}
 func (Labels) isProgram()      {}
+func (RecBinding) isNode()     {}
+func (Label) isNode()          {}
 func (Label) isSimpleExpr()    {}
 func (Label) isValue()         {}
+func (Quote) isNode()          {}
 func (Quote) isSimpleExpr()    {}
 func (Quote) isValue()         {}
+func (Symbol) isNode()         {}
 func (Symbol) isSimpleExpr()   {}
 func (Symbol) isValue()        {}
+func (ApplyValue) isNode()     {}
 func (ApplyValue) isValue()    {}
+func (BeginValue) isNode()     {}
 func (BeginValue) isValue()    {}
+func (IfValue) isNode()        {}
 func (IfValue) isValue()       {}
+func (LetValue) isNode()       {}
 func (LetValue) isValue()      {}
+func (PrimValue) isNode()      {}
 func (PrimValue) isValue()     {}
+func (ValuePrim) isNode()      {}
+
+// String returns the text of x, as written by Unparse.
+func (x Symbol) String() string { return string(x) }
+
+// Valid reports whether x is a valid Symbol.
+func (x Symbol) Valid() bool { return term.Symbol(x).Valid() }
+
+// The primitives of EffectPrim.
+const (
+	EffectPrimSetCar         EffectPrim = 13 // set-car
+	EffectPrimSetCdr         EffectPrim = 14 // set-cdr
+	EffectPrimVectorSet      EffectPrim = 15 // vector-set
+	EffectPrimSetBox         EffectPrim = 16 // set-box
+	EffectPrimClosureCodeSet EffectPrim = 33 // closure-code-set
+	EffectPrimClosureDataSet EffectPrim = 34 // closure-data-set
+)
+
+// Prim returns the description of x, or nil if x is not one of
+// EffectPrim's primitives.
+func (x EffectPrim) Prim() *lang.Prim { return effectPrimTable[x] }
+
+// String returns the name of x, as written by Unparse.
+func (x EffectPrim) String() string {
+	if p := x.Prim(); p != nil {
+		return p.Name
+	}
+	return "EffectPrim(" + strconv.Itoa(int(x)) + ")"
+}
+
+// Valid reports whether x is one of EffectPrim's primitives.
+func (x EffectPrim) Valid() bool { return x.Prim() != nil }
+
+// LookupEffectPrim returns the primitive of EffectPrim with the given
+// name, if any.
+func LookupEffectPrim(name string) (EffectPrim, bool) {
+	for x, p := range effectPrimTable {
+		if p.Name == name {
+			return x, true
+		}
+	}
+	return 0, false
+}
+
+var effectPrimTable = map[EffectPrim]*lang.Prim{
+	EffectPrimSetCar:         {Name: "set-car", Code: 13, Arity: 2, Class: lang.Effect},
+	EffectPrimSetCdr:         {Name: "set-cdr", Code: 14, Arity: 2, Class: lang.Effect},
+	EffectPrimVectorSet:      {Name: "vector-set", Code: 15, Arity: 3, Class: lang.Effect},
+	EffectPrimSetBox:         {Name: "set-box", Code: 16, Arity: 2, Class: lang.Effect},
+	EffectPrimClosureCodeSet: {Name: "closure-code-set", Code: 33, Arity: 2, Class: lang.Effect},
+	EffectPrimClosureDataSet: {Name: "closure-data-set", Code: 34, Arity: 3, Class: lang.Effect},
+}
+
+// The primitives of PredicatePrim.
+const (
+	PredicatePrimLt          PredicatePrim = 17 // lt
+	PredicatePrimLe          PredicatePrim = 18 // le
+	PredicatePrimNumEq       PredicatePrim = 19 // num-eq
+	PredicatePrimGe          PredicatePrim = 20 // ge
+	PredicatePrimGt          PredicatePrim = 21 // gt
+	PredicatePrimEq          PredicatePrim = 22 // eq
+	PredicatePrimIsBoolean   PredicatePrim = 23 // is-boolean
+	PredicatePrimIsBox       PredicatePrim = 24 // is-box
+	PredicatePrimIsNull      PredicatePrim = 25 // is-null
+	PredicatePrimIsPair      PredicatePrim = 26 // is-pair
+	PredicatePrimIsProcedure PredicatePrim = 27 // is-procedure
+	PredicatePrimIsVector    PredicatePrim = 28 // is-vector
+)
+
+// Prim returns the description of x, or nil if x is not one of
+// PredicatePrim's primitives.
+func (x PredicatePrim) Prim() *lang.Prim { return predicatePrimTable[x] }
+
+// String returns the name of x, as written by Unparse.
+func (x PredicatePrim) String() string {
+	if p := x.Prim(); p != nil {
+		return p.Name
+	}
+	return "PredicatePrim(" + strconv.Itoa(int(x)) + ")"
+}
+
+// Valid reports whether x is one of PredicatePrim's primitives.
+func (x PredicatePrim) Valid() bool { return x.Prim() != nil }
+
+// LookupPredicatePrim returns the primitive of PredicatePrim with the given
+// name, if any.
+func LookupPredicatePrim(name string) (PredicatePrim, bool) {
+	for x, p := range predicatePrimTable {
+		if p.Name == name {
+			return x, true
+		}
+	}
+	return 0, false
+}
+
+var predicatePrimTable = map[PredicatePrim]*lang.Prim{
+	PredicatePrimLt:          {Name: "lt", Code: 17, Arity: 2, Class: lang.Predicate, Pure: true},
+	PredicatePrimLe:          {Name: "le", Code: 18, Arity: 2, Class: lang.Predicate, Pure: true},
+	PredicatePrimNumEq:       {Name: "num-eq", Code: 19, Arity: 2, Class: lang.Predicate, Pure: true},
+	PredicatePrimGe:          {Name: "ge", Code: 20, Arity: 2, Class: lang.Predicate, Pure: true},
+	PredicatePrimGt:          {Name: "gt", Code: 21, Arity: 2, Class: lang.Predicate, Pure: true},
+	PredicatePrimEq:          {Name: "eq", Code: 22, Arity: 2, Class: lang.Predicate, Pure: true},
+	PredicatePrimIsBoolean:   {Name: "is-boolean", Code: 23, Arity: 1, Class: lang.Predicate, Pure: true},
+	PredicatePrimIsBox:       {Name: "is-box", Code: 24, Arity: 1, Class: lang.Predicate, Pure: true},
+	PredicatePrimIsNull:      {Name: "is-null", Code: 25, Arity: 1, Class: lang.Predicate, Pure: true},
+	PredicatePrimIsPair:      {Name: "is-pair", Code: 26, Arity: 1, Class: lang.Predicate, Pure: true},
+	PredicatePrimIsProcedure: {Name: "is-procedure", Code: 27, Arity: 1, Class: lang.Predicate, Pure: true},
+	PredicatePrimIsVector:    {Name: "is-vector", Code: 28, Arity: 1, Class: lang.Predicate, Pure: true},
+}
+
+// The primitives of ValuePrim.
+const (
+	ValuePrimAdd          ValuePrim = 1  // add
+	ValuePrimSub          ValuePrim = 2  // sub
+	ValuePrimMul          ValuePrim = 3  // mul
+	ValuePrimDiv          ValuePrim = 4  // div
+	ValuePrimCar          ValuePrim = 5  // car
+	ValuePrimCdr          ValuePrim = 6  // cdr
+	ValuePrimCons         ValuePrim = 7  // cons
+	ValuePrimMakeVector   ValuePrim = 8  // make-vector
+	ValuePrimVectorRef    ValuePrim = 9  // vector-ref
+	ValuePrimVectorLength ValuePrim = 10 // vector-length
+	ValuePrimBox          ValuePrim = 11 // box
+	ValuePrimUnbox        ValuePrim = 12 // unbox
+	ValuePrimVoid         ValuePrim = 29 // void
+	ValuePrimMakeClosure  ValuePrim = 30 // make-closure
+	ValuePrimClosureCode  ValuePrim = 31 // closure-code
+	ValuePrimClosureRef   ValuePrim = 32 // closure-ref
+)
+
+// Prim returns the description of x, or nil if x is not one of
+// ValuePrim's primitives.
+func (x ValuePrim) Prim() *lang.Prim { return valuePrimTable[x] }
+
+// String returns the name of x, as written by Unparse.
+func (x ValuePrim) String() string {
+	if p := x.Prim(); p != nil {
+		return p.Name
+	}
+	return "ValuePrim(" + strconv.Itoa(int(x)) + ")"
+}
+
+// Valid reports whether x is one of ValuePrim's primitives.
+func (x ValuePrim) Valid() bool { return x.Prim() != nil }
+
+// LookupValuePrim returns the primitive of ValuePrim with the given
+// name, if any.
+func LookupValuePrim(name string) (ValuePrim, bool) {
+	for x, p := range valuePrimTable {
+		if p.Name == name {
+			return x, true
+		}
+	}
+	return 0, false
+}
+
+var valuePrimTable = map[ValuePrim]*lang.Prim{
+	ValuePrimAdd:          {Name: "add", Code: 1, Arity: 2, Class: lang.Value, Pure: true},
+	ValuePrimSub:          {Name: "sub", Code: 2, Arity: 2, Class: lang.Value, Pure: true},
+	ValuePrimMul:          {Name: "mul", Code: 3, Arity: 2, Class: lang.Value, Pure: true},
+	ValuePrimDiv:          {Name: "div", Code: 4, Arity: 2, Class: lang.Value, Pure: true},
+	ValuePrimCar:          {Name: "car", Code: 5, Arity: 1, Class: lang.Value, Pure: true},
+	ValuePrimCdr:          {Name: "cdr", Code: 6, Arity: 1, Class: lang.Value, Pure: true},
+	ValuePrimCons:         {Name: "cons", Code: 7, Arity: 2, Class: lang.Value, Pure: true, Alloc: true},
+	ValuePrimMakeVector:   {Name: "make-vector", Code: 8, Arity: 1, Class: lang.Value, Pure: true, Alloc: true},
+	ValuePrimVectorRef:    {Name: "vector-ref", Code: 9, Arity: 2, Class: lang.Value, Pure: true},
+	ValuePrimVectorLength: {Name: "vector-length", Code: 10, Arity: 1, Class: lang.Value, Pure: true},
+	ValuePrimBox:          {Name: "box", Code: 11, Arity: 1, Class: lang.Value, Pure: true, Alloc: true},
+	ValuePrimUnbox:        {Name: "unbox", Code: 12, Arity: 1, Class: lang.Value, Pure: true},
+	ValuePrimVoid:         {Name: "void", Code: 29, Arity: 0, Class: lang.Value, Pure: true},
+	ValuePrimMakeClosure:  {Name: "make-closure", Code: 30, Arity: 2, Class: lang.Value, Pure: true, Alloc: true},
+	ValuePrimClosureCode:  {Name: "closure-code", Code: 31, Arity: 1, Class: lang.Value, Pure: true},
+	ValuePrimClosureRef:   {Name: "closure-ref", Code: 32, Arity: 2, Class: lang.Value, Pure: true},
+}
@@ -0,0 +1,357 @@
+// Code generated by Hermes. DO NOT EDIT.
+
+package L16
+
+import (
+	"reflect"
+
+	"github.com/mdempsky/hermes/lang"
+)
+
+// Language describes L16.
+var Language = &lang.Language{
+	Name:  "L16",
+	Path:  "github.com/mdempsky/hermes/example/lang/L16",
+	Entry: "Program",
+	Var:   "Symbol",
+	Meta:  "Meta",
+	Defs: []*lang.Def{
+		{
+			Name:   "Binding",
+			Kind:   lang.Product,
+			From:   "L16",
+			GoType: reflect.TypeFor[Binding](),
+			Fields: []lang.Field{
+				{Name: "Var", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}, Bind: true},
+				{Name: "Val", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Value"}},
+			},
+		},
+		{
+			Name:   "Const",
+			Kind:   lang.NonTerminal,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Const](),
+			Cons: []*lang.Con{
+				{
+					Name:   "Int",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Int](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.Int, Name: "int"}},
+					},
+				},
+				{
+					Name:   "Nil",
+					From:   "Lsrc",
+					GoType: reflect.TypeFor[Nil](),
+				},
+			},
+		},
+		{
+			Name:   "Effect",
+			Kind:   lang.NonTerminal,
+			From:   "L16",
+			GoType: reflect.TypeFor[Effect](),
+			Cons: []*lang.Con{
+				{
+					Name:   "ApplyEffect",
+					From:   "L16",
+					GoType: reflect.TypeFor[ApplyEffect](),
+					Fields: []lang.Field{
+						{Name: "Fun", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Args", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}}},
+					},
+				},
+				{
+					Name:   "BeginEffect",
+					From:   "L16",
+					GoType: reflect.TypeFor[BeginEffect](),
+					Fields: []lang.Field{
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}}},
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}},
+					},
+				},
+				{
+					Name:   "IfEffect",
+					From:   "L16",
+					GoType: reflect.TypeFor[IfEffect](),
+					Fields: []lang.Field{
+						{Name: "Cond", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+						{Name: "Then", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}},
+						{Name: "Else", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}},
+					},
+				},
+				{
+					Name:   "LetEffect",
+					From:   "L16",
+					GoType: reflect.TypeFor[LetEffect](),
+					Fields: []lang.Field{
+						{Name: "Bindings", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Product, Name: "Binding"}}},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}, Scope: true},
+					},
+				},
+				{
+					Name:   "Nop",
+					From:   "L16",
+					GoType: reflect.TypeFor[Nop](),
+				},
+				{
+					Name:   "PrimEffect",
+					From:   "L16",
+					GoType: reflect.TypeFor[PrimEffect](),
+					Fields: []lang.Field{
+						{Name: "Prim", Type: &lang.Type{Kind: lang.Terminal, Name: "EffectPrim"}},
+						{Name: "Args", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}}},
+					},
+				},
+			},
+		},
+		{
+			Name:   "EffectPrim",
+			Kind:   lang.Terminal,
+			From:   "L16",
+			GoType: reflect.TypeFor[EffectPrim](),
+			Prims: []*lang.Prim{
+				effectPrimTable[EffectPrimSetCar],
+				effectPrimTable[EffectPrimSetCdr],
+				effectPrimTable[EffectPrimVectorSet],
+				effectPrimTable[EffectPrimSetBox],
+				effectPrimTable[EffectPrimClosureCodeSet],
+				effectPrimTable[EffectPrimClosureDataSet],
+			},
+		},
+		{
+			Name:   "LambdaExpr",
+			Kind:   lang.NonTerminal,
+			From:   "L8",
+			GoType: reflect.TypeFor[LambdaExpr](),
+			Cons: []*lang.Con{
+				{
+					Name:   "Lambda",
+					From:   "L16",
+					GoType: reflect.TypeFor[Lambda](),
+					Fields: []lang.Field{
+						{Name: "Params", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}}, Bind: true},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Value"}, Scope: true},
+					},
+				},
+			},
+		},
+		{
+			Name:   "Predicate",
+			Kind:   lang.NonTerminal,
+			From:   "L16",
+			GoType: reflect.TypeFor[Predicate](),
+			Cons: []*lang.Con{
+				{
+					Name:   "BeginPred",
+					From:   "L16",
+					GoType: reflect.TypeFor[BeginPred](),
+					Fields: []lang.Field{
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}}},
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+					},
+				},
+				{
+					Name:   "False",
+					From:   "L16",
+					GoType: reflect.TypeFor[False](),
+				},
+				{
+					Name:   "IfPred",
+					From:   "L16",
+					GoType: reflect.TypeFor[IfPred](),
+					Fields: []lang.Field{
+						{Name: "Cond", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+						{Name: "Then", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+						{Name: "Else", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+					},
+				},
+				{
+					Name:   "LetPred",
+					From:   "L16",
+					GoType: reflect.TypeFor[LetPred](),
+					Fields: []lang.Field{
+						{Name: "Bindings", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Product, Name: "Binding"}}},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}, Scope: true},
+					},
+				},
+				{
+					Name:   "PrimPred",
+					From:   "L16",
+					GoType: reflect.TypeFor[PrimPred](),
+					Fields: []lang.Field{
+						{Name: "Prim", Type: &lang.Type{Kind: lang.Terminal, Name: "PredicatePrim"}},
+						{Name: "Args", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}}},
+					},
+				},
+				{
+					Name:   "True",
+					From:   "L16",
+					GoType: reflect.TypeFor[True](),
+				},
+			},
+		},
+		{
+			Name:   "PredicatePrim",
+			Kind:   lang.Terminal,
+			From:   "L16",
+			GoType: reflect.TypeFor[PredicatePrim](),
+			Prims: []*lang.Prim{
+				predicatePrimTable[PredicatePrimLt],
+				predicatePrimTable[PredicatePrimLe],
+				predicatePrimTable[PredicatePrimNumEq],
+				predicatePrimTable[PredicatePrimGe],
+				predicatePrimTable[PredicatePrimGt],
+				predicatePrimTable[PredicatePrimEq],
+				predicatePrimTable[PredicatePrimIsBoolean],
+				predicatePrimTable[PredicatePrimIsBox],
+				predicatePrimTable[PredicatePrimIsNull],
+				predicatePrimTable[PredicatePrimIsPair],
+				predicatePrimTable[PredicatePrimIsProcedure],
+				predicatePrimTable[PredicatePrimIsVector],
+			},
+		},
+		{
+			Name:   "Program",
+			Kind:   lang.NonTerminal,
+			From:   "L14",
+			GoType: reflect.TypeFor[Program](),
+			Cons: []*lang.Con{
+				{
+					Name:   "Labels",
+					From:   "L14",
+					GoType: reflect.TypeFor[Labels](),
+					Fields: []lang.Field{
+						{Name: "Bindings", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Product, Name: "RecBinding"}}, Scope: true},
+						{Name: "Entry", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}, Scope: true},
+					},
+				},
+			},
+		},
+		{
+			Name:   "RecBinding",
+			Kind:   lang.Product,
+			From:   "L8",
+			GoType: reflect.TypeFor[RecBinding](),
+			Fields: []lang.Field{
+				{Name: "Var", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}, Bind: true},
+				{Name: "Val", Type: &lang.Type{Kind: lang.NonTerminal, Name: "LambdaExpr"}},
+			},
+		},
+		{
+			Name:   "SimpleExpr",
+			Kind:   lang.NonTerminal,
+			From:   "L15",
+			GoType: reflect.TypeFor[SimpleExpr](),
+			IsAlso: []string{"Value"},
+			Embeds: []string{"Symbol"},
+			Cons: []*lang.Con{
+				{
+					Name:   "Label",
+					From:   "L16",
+					GoType: reflect.TypeFor[Label](),
+					Fields: []lang.Field{
+						{Name: "Name", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}},
+					},
+				},
+				{
+					Name:   "Quote",
+					From:   "L16",
+					GoType: reflect.TypeFor[Quote](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Const"}},
+					},
+				},
+			},
+		},
+		{
+			Name:   "Symbol",
+			Kind:   lang.Terminal,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Symbol](),
+			IsAlso: []string{"SimpleExpr", "Value"},
+		},
+		{
+			Name:   "Value",
+			Kind:   lang.NonTerminal,
+			From:   "L16",
+			GoType: reflect.TypeFor[Value](),
+			Embeds: []string{"SimpleExpr"},
+			Cons: []*lang.Con{
+				{
+					Name:   "ApplyValue",
+					From:   "L16",
+					GoType: reflect.TypeFor[ApplyValue](),
+					Fields: []lang.Field{
+						{Name: "Fun", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Args", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}}},
+					},
+				},
+				{
+					Name:   "BeginValue",
+					From:   "L16",
+					GoType: reflect.TypeFor[BeginValue](),
+					Fields: []lang.Field{
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}}},
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Value"}},
+					},
+				},
+				{
+					Name:   "IfValue",
+					From:   "L16",
+					GoType: reflect.TypeFor[IfValue](),
+					Fields: []lang.Field{
+						{Name: "Cond", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+						{Name: "Then", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Value"}},
+						{Name: "Else", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Value"}},
+					},
+				},
+				{
+					Name:   "LetValue",
+					From:   "L16",
+					GoType: reflect.TypeFor[LetValue](),
+					Fields: []lang.Field{
+						{Name: "Bindings", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Product, Name: "Binding"}}},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Value"}, Scope: true},
+					},
+				},
+				{
+					Name:   "PrimValue",
+					From:   "L16",
+					GoType: reflect.TypeFor[PrimValue](),
+					Fields: []lang.Field{
+						{Name: "Prim", Type: &lang.Type{Kind: lang.Terminal, Name: "ValuePrim"}},
+						{Name: "Args", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}}},
+					},
+				},
+			},
+		},
+		{
+			Name:   "ValuePrim",
+			Kind:   lang.Terminal,
+			From:   "L16",
+			GoType: reflect.TypeFor[ValuePrim](),
+			Prims: []*lang.Prim{
+				valuePrimTable[ValuePrimAdd],
+				valuePrimTable[ValuePrimSub],
+				valuePrimTable[ValuePrimMul],
+				valuePrimTable[ValuePrimDiv],
+				valuePrimTable[ValuePrimCar],
+				valuePrimTable[ValuePrimCdr],
+				valuePrimTable[ValuePrimCons],
+				valuePrimTable[ValuePrimMakeVector],
+				valuePrimTable[ValuePrimVectorRef],
+				valuePrimTable[ValuePrimVectorLength],
+				valuePrimTable[ValuePrimBox],
+				valuePrimTable[ValuePrimUnbox],
+				valuePrimTable[ValuePrimVoid],
+				valuePrimTable[ValuePrimMakeClosure],
+				valuePrimTable[ValuePrimClosureCode],
+				valuePrimTable[ValuePrimClosureRef],
+			},
+		},
+	},
+}
+
+func init() { lang.Register(Language) }
@@ -2,196 +2,291 @@
 
 package L22
 
+import (
+	"github.com/mdempsky/hermes/example/term"
+	"github.com/mdempsky/hermes/lang"
+)
+
 type terminal int
 
-type (
-	Binding struct {
-		Var Symbol
-		Val Value
-	}
-	Closure struct {
-		X Symbol
-		L Symbol
-		F []Symbol
-	}
-	EffectPrim    terminal // from L17
-	PredicatePrim terminal // from L16
-	Primitive     terminal // from L13
-	RecBinding    struct {
-		Var Symbol
-		Val LambdaExpr
-	}
-	Symbol    terminal // from Lsrc
-	ValuePrim terminal // from L17
-)
+// Entry is the entry non-terminal of L22.
+type Entry = Program
+
+// A Node is a terminal, product type, or production value.
+type Node interface{ isNode() }
 
 type (
-	Const interface{ isConst() }
-	Nil   struct{}
+	RecBinding struct {
+		Var  Symbol
+		Val  LambdaExpr
+		Meta lang.Meta[any]
+	}
+	Symbol term.Symbol // from Lsrc
 )
 
 type (
-	Effect      interface{ isEffect() }
+	Effect interface {
+		Node
+		isEffect()
+	}
 	ApplyEffect struct {
 		Fun  SimpleExpr
 		Args []SimpleExpr
+		Meta lang.Meta[any]
 	}
 	BeginEffect struct {
 		Init []Effect
 		X    Effect
+		Meta lang.Meta[any]
 	}
 	IfEffect struct {
 		Cond       Predicate
 		Then, Else Effect
+		Meta       lang.Meta[any]
 	}
 	MSet struct {
 		Ptr    SimpleExpr
 		Index  *SimpleExpr
 		Offset int64
 		Data   SimpleExpr
+		Meta   lang.Meta[any]
 	}
-	Nop struct{}
+	Nop struct{ Meta lang.Meta[any] }
 	Set struct {
-		Lhs Symbol
-		Rhs Rhs
+		Lhs  Symbol
+		Rhs  Rhs
+		Meta lang.Meta[any]
 	}
 )
 
 type (
-	LabelsBody interface{ isLabelsBody() }
-)
-
-type (
-	LambdaExpr interface{ isLambdaExpr() }
-	Lambda     struct {
+	LambdaExpr interface {
+		Node
+		isLambdaExpr()
+	}
+	Lambda struct {
 		Params, Locals []Symbol
 		Body           Value
+		Meta           lang.Meta[any]
 	}
 )
 
 type (
-	Predicate interface{ isPredicate() }
+	Predicate interface {
+		Node
+		isPredicate()
+	}
 	BeginPred struct {
 		Init []Effect
 		X    Predicate
+		Meta lang.Meta[any]
+	}
+	Eql struct {
+		X, Y SimpleExpr
+		Meta lang.Meta[any]
+	}
+	False  struct{ Meta lang.Meta[any] }
+	IfPred struct {
+		Cond, Then, Else Predicate
+		Meta             lang.Meta[any]
+	}
+	Leq struct {
+		X, Y SimpleExpr
+		Meta lang.Meta[any]
 	}
-	Eql    struct{ X, Y SimpleExpr }
-	False  struct{}
-	IfPred struct{ Cond, Then, Else Predicate }
-	Leq    struct{ X, Y SimpleExpr }
-	Lss    struct{ X, Y SimpleExpr }
-	True   struct{}
+	Lss struct {
+		X, Y SimpleExpr
+		Meta lang.Meta[any]
+	}
+	True struct{ Meta lang.Meta[any] }
 )
 
 type (
-	Program interface{ isProgram() }
-	Labels  struct {
+	Program interface {
+		Node
+		isProgram()
+	}
+	Labels struct {
 		Bindings []RecBinding
 		Entry    Symbol
+		Meta     lang.Meta[any]
 	}
 )
 
 type (
 	Rhs interface {
+		Node
 		Value
 		isRhs()
 	}
 	Alloc struct {
 		Tag  int64
 		Size SimpleExpr
+		Meta lang.Meta[any]
 	}
 	ApplyValue struct {
 		Fun  SimpleExpr
 		Args []SimpleExpr
+		Meta lang.Meta[any]
 	}
 )
 
 type (
 	SimpleExpr interface {
+		Node
 		Rhs
 		Value
 		isSimpleExpr()
 	}
-	Add        struct{ X, Y SimpleExpr }
-	Divide     struct{ X, Y SimpleExpr }
-	Int        struct{ Int int64 }
-	Label      struct{ Name Symbol }
-	LogicalAnd struct{ X, Y SimpleExpr }
-	MRef       struct {
+	Add struct {
+		X, Y SimpleExpr
+		Meta lang.Meta[any]
+	}
+	Divide struct {
+		X, Y SimpleExpr
+		Meta lang.Meta[any]
+	}
+	Int struct {
+		Int  int64
+		Meta lang.Meta[any]
+	}
+	Label struct {
+		Name Symbol
+		Meta lang.Meta[any]
+	}
+	LogicalAnd struct {
+		X, Y SimpleExpr
+		Meta lang.Meta[any]
+	}
+	MRef struct {
 		Ptr    SimpleExpr
 		Index  *SimpleExpr
 		Offset int64
+		Meta   lang.Meta[any]
+	}
+	Multiple struct {
+		X, Y SimpleExpr
+		Meta lang.Meta[any]
+	}
+	ShiftLeft struct {
+		X, Y SimpleExpr
+		Meta lang.Meta[any]
+	}
+	ShiftRight struct {
+		X, Y SimpleExpr
+		Meta lang.Meta[any]
+	}
+	Subtract struct {
+		X, Y SimpleExpr
+		Meta lang.Meta[any]
 	}
-	Multiple   struct{ X, Y SimpleExpr }
-	ShiftLeft  struct{ X, Y SimpleExpr }
-	ShiftRight struct{ X, Y SimpleExpr }
-	Subtract   struct{ X, Y SimpleExpr }
 )
 
 type (
-	Value      interface{ isValue() }
+	Value interface {
+		Node
+		isValue()
+	}
 	BeginValue struct {
 		Init []Effect
 		X    Value
+		Meta lang.Meta[any]
 	}
 	IfValue struct {
 		Cond       Predicate
 		Then, Else Value
+		Meta       lang.Meta[any]
 	}
 )
 
-func (Nil) isConst()             {}
+func (ApplyEffect) isNode()      {}
 func (ApplyEffect) isEffect()    {}
+func (BeginEffect) isNode()      {}
 func (BeginEffect) isEffect()    {}
+func (IfEffect) isNode()         {}
 func (IfEffect) isEffect()       {}
+func (MSet) isNode()             {}
 func (MSet) isEffect()           {}
+func (Nop) isNode()              {}
 func (Nop) isEffect()            {}
+func (Set) isNode()              {}
 func (Set) isEffect()            {}
+func (Lambda) isNode()           {}
 func (Lambda) isLambdaExpr()     {}
+func (BeginPred) isNode()        {}
 func (BeginPred) isPredicate()   {}
+func (Eql) isNode()              {}
 func (Eql) isPredicate()         {}
+func (False) isNode()            {}
 func (False) isPredicate()       {}
+func (IfPred) isNode()           {}
 func (IfPred) isPredicate()      {}
+func (Leq) isNode()              {}
 func (Leq) isPredicate()         {}
+func (Lss) isNode()              {}
 func (Lss) isPredicate()         {}
+func (True) isNode()             {}
 func (True) isPredicate()        {}
+func (Labels) isNode()           {}
 func (Labels) isProgram()        {}
+func (RecBinding) isNode()       {}
+func (Alloc) isNode()            {}
 func (Alloc) isRhs()             {}
 func (Alloc) isValue()           {}
+func (ApplyValue) isNode()       {}
 func (ApplyValue) isRhs()        {}
 func (ApplyValue) isValue()      {}
+func (Add) isNode()              {}
 func (Add) isRhs()               {}
 func (Add) isSimpleExpr()        {}
 func (Add) isValue()             {}
+func (Divide) isNode()           {}
 func (Divide) isRhs()            {}
 func (Divide) isSimpleExpr()     {}
 func (Divide) isValue()          {}
+func (Int) isNode()              {}
 func (Int) isRhs()               {}
 func (Int) isSimpleExpr()        {}
 func (Int) isValue()             {}
+func (Label) isNode()            {}
 func (Label) isRhs()             {}
 func (Label) isSimpleExpr()      {}
 func (Label) isValue()           {}
+func (LogicalAnd) isNode()       {}
 func (LogicalAnd) isRhs()        {}
 func (LogicalAnd) isSimpleExpr() {}
 func (LogicalAnd) isValue()      {}
+func (MRef) isNode()             {}
 func (MRef) isRhs()              {}
 func (MRef) isSimpleExpr()       {}
 func (MRef) isValue()            {}
+func (Multiple) isNode()         {}
 func (Multiple) isRhs()          {}
 func (Multiple) isSimpleExpr()   {}
 func (Multiple) isValue()        {}
+func (ShiftLeft) isNode()        {}
 func (ShiftLeft) isRhs()         {}
 func (ShiftLeft) isSimpleExpr()  {}
 func (ShiftLeft) isValue()       {}
+func (ShiftRight) isNode()       {}
 func (ShiftRight) isRhs()        {}
 func (ShiftRight) isSimpleExpr() {}
 func (ShiftRight) isValue()      {}
+func (Subtract) isNode()         {}
 func (Subtract) isRhs()          {}
 func (Subtract) isSimpleExpr()   {}
 func (Subtract) isValue()        {}
+func (Symbol) isNode()           {}
 func (Symbol) isRhs()            {}
 func (Symbol) isSimpleExpr()     {}
 func (Symbol) isValue()          {}
+func (BeginValue) isNode()       {}
 func (BeginValue) isValue()      {}
+func (IfValue) isNode()          {}
 func (IfValue) isValue()         {}
+
+// String returns the text of x, as written by Unparse.
+func (x Symbol) String() string { return string(x) }
+
+// Valid reports whether x is a valid Symbol.
+func (x Symbol) Valid() bool { return term.Symbol(x).Valid() }
@@ -0,0 +1,354 @@
+// Code generated by Hermes. DO NOT EDIT.
+
+package L22
+
+import (
+	"reflect"
+
+	"github.com/mdempsky/hermes/lang"
+)
+
+// Language describes L22.
+var Language = &lang.Language{
+	Name:  "L22",
+	Path:  "github.com/mdempsky/hermes/example/lang/L22",
+	Entry: "Program",
+	Var:   "Symbol",
+	Meta:  "Meta",
+	Defs: []*lang.Def{
+		{
+			Name:   "Effect",
+			Kind:   lang.NonTerminal,
+			From:   "L16",
+			GoType: reflect.TypeFor[Effect](),
+			Cons: []*lang.Con{
+				{
+					Name:   "ApplyEffect",
+					From:   "L16",
+					GoType: reflect.TypeFor[ApplyEffect](),
+					Fields: []lang.Field{
+						{Name: "Fun", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Args", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}}},
+					},
+				},
+				{
+					Name:   "BeginEffect",
+					From:   "L16",
+					GoType: reflect.TypeFor[BeginEffect](),
+					Fields: []lang.Field{
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}}},
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}},
+					},
+				},
+				{
+					Name:   "IfEffect",
+					From:   "L16",
+					GoType: reflect.TypeFor[IfEffect](),
+					Fields: []lang.Field{
+						{Name: "Cond", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+						{Name: "Then", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}},
+						{Name: "Else", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}},
+					},
+				},
+				{
+					Name:   "MSet",
+					From:   "L22",
+					GoType: reflect.TypeFor[MSet](),
+					Fields: []lang.Field{
+						{Name: "Ptr", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Index", Type: &lang.Type{Kind: lang.Optional, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}}},
+						{Name: "Offset", Type: &lang.Type{Kind: lang.Int, Name: "int64"}},
+						{Name: "Data", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "Nop",
+					From:   "L16",
+					GoType: reflect.TypeFor[Nop](),
+				},
+				{
+					Name:   "Set",
+					From:   "L19",
+					GoType: reflect.TypeFor[Set](),
+					Fields: []lang.Field{
+						{Name: "Lhs", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}},
+						{Name: "Rhs", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Rhs"}},
+					},
+				},
+			},
+		},
+		{
+			Name:   "LambdaExpr",
+			Kind:   lang.NonTerminal,
+			From:   "L8",
+			GoType: reflect.TypeFor[LambdaExpr](),
+			Cons: []*lang.Con{
+				{
+					Name:   "Lambda",
+					From:   "L18",
+					GoType: reflect.TypeFor[Lambda](),
+					Fields: []lang.Field{
+						{Name: "Params", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}}, Bind: true},
+						{Name: "Locals", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}}, Bind: true},
+						{Name: "Body", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Value"}, Scope: true},
+					},
+				},
+			},
+		},
+		{
+			Name:   "Predicate",
+			Kind:   lang.NonTerminal,
+			From:   "L16",
+			GoType: reflect.TypeFor[Predicate](),
+			Cons: []*lang.Con{
+				{
+					Name:   "BeginPred",
+					From:   "L16",
+					GoType: reflect.TypeFor[BeginPred](),
+					Fields: []lang.Field{
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}}},
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+					},
+				},
+				{
+					Name:   "Eql",
+					From:   "L22",
+					GoType: reflect.TypeFor[Eql](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Y", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "False",
+					From:   "L16",
+					GoType: reflect.TypeFor[False](),
+				},
+				{
+					Name:   "IfPred",
+					From:   "L16",
+					GoType: reflect.TypeFor[IfPred](),
+					Fields: []lang.Field{
+						{Name: "Cond", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+						{Name: "Then", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+						{Name: "Else", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+					},
+				},
+				{
+					Name:   "Leq",
+					From:   "L22",
+					GoType: reflect.TypeFor[Leq](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Y", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "Lss",
+					From:   "L22",
+					GoType: reflect.TypeFor[Lss](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Y", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "True",
+					From:   "L16",
+					GoType: reflect.TypeFor[True](),
+				},
+			},
+		},
+		{
+			Name:   "Program",
+			Kind:   lang.NonTerminal,
+			From:   "L14",
+			GoType: reflect.TypeFor[Program](),
+			Cons: []*lang.Con{
+				{
+					Name:   "Labels",
+					From:   "L14",
+					GoType: reflect.TypeFor[Labels](),
+					Fields: []lang.Field{
+						{Name: "Bindings", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.Product, Name: "RecBinding"}}, Scope: true},
+						{Name: "Entry", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}, Scope: true},
+					},
+				},
+			},
+		},
+		{
+			Name:   "RecBinding",
+			Kind:   lang.Product,
+			From:   "L8",
+			GoType: reflect.TypeFor[RecBinding](),
+			Fields: []lang.Field{
+				{Name: "Var", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}, Bind: true},
+				{Name: "Val", Type: &lang.Type{Kind: lang.NonTerminal, Name: "LambdaExpr"}},
+			},
+		},
+		{
+			Name:   "Rhs",
+			Kind:   lang.NonTerminal,
+			From:   "L19",
+			GoType: reflect.TypeFor[Rhs](),
+			IsAlso: []string{"Value"},
+			Embeds: []string{"SimpleExpr"},
+			Cons: []*lang.Con{
+				{
+					Name:   "Alloc",
+					From:   "L19",
+					GoType: reflect.TypeFor[Alloc](),
+					Fields: []lang.Field{
+						{Name: "Tag", Type: &lang.Type{Kind: lang.Int, Name: "int64"}},
+						{Name: "Size", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "ApplyValue",
+					From:   "L19",
+					GoType: reflect.TypeFor[ApplyValue](),
+					Fields: []lang.Field{
+						{Name: "Fun", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Args", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}}},
+					},
+				},
+			},
+		},
+		{
+			Name:   "SimpleExpr",
+			Kind:   lang.NonTerminal,
+			From:   "L15",
+			GoType: reflect.TypeFor[SimpleExpr](),
+			IsAlso: []string{"Rhs", "Value"},
+			Embeds: []string{"Symbol"},
+			Cons: []*lang.Con{
+				{
+					Name:   "Add",
+					From:   "L22",
+					GoType: reflect.TypeFor[Add](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Y", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "Divide",
+					From:   "L22",
+					GoType: reflect.TypeFor[Divide](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Y", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "Int",
+					From:   "L21",
+					GoType: reflect.TypeFor[Int](),
+					Fields: []lang.Field{
+						{Name: "Int", Type: &lang.Type{Kind: lang.Int, Name: "int64"}},
+					},
+				},
+				{
+					Name:   "Label",
+					From:   "L16",
+					GoType: reflect.TypeFor[Label](),
+					Fields: []lang.Field{
+						{Name: "Name", Type: &lang.Type{Kind: lang.Terminal, Name: "Symbol"}},
+					},
+				},
+				{
+					Name:   "LogicalAnd",
+					From:   "L22",
+					GoType: reflect.TypeFor[LogicalAnd](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Y", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "MRef",
+					From:   "L22",
+					GoType: reflect.TypeFor[MRef](),
+					Fields: []lang.Field{
+						{Name: "Ptr", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Index", Type: &lang.Type{Kind: lang.Optional, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}}},
+						{Name: "Offset", Type: &lang.Type{Kind: lang.Int, Name: "int64"}},
+					},
+				},
+				{
+					Name:   "Multiple",
+					From:   "L22",
+					GoType: reflect.TypeFor[Multiple](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Y", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "ShiftLeft",
+					From:   "L22",
+					GoType: reflect.TypeFor[ShiftLeft](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Y", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "ShiftRight",
+					From:   "L22",
+					GoType: reflect.TypeFor[ShiftRight](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Y", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+				{
+					Name:   "Subtract",
+					From:   "L22",
+					GoType: reflect.TypeFor[Subtract](),
+					Fields: []lang.Field{
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+						{Name: "Y", Type: &lang.Type{Kind: lang.NonTerminal, Name: "SimpleExpr"}},
+					},
+				},
+			},
+		},
+		{
+			Name:   "Symbol",
+			Kind:   lang.Terminal,
+			From:   "Lsrc",
+			GoType: reflect.TypeFor[Symbol](),
+			IsAlso: []string{"Rhs", "SimpleExpr", "Value"},
+		},
+		{
+			Name:   "Value",
+			Kind:   lang.NonTerminal,
+			From:   "L16",
+			GoType: reflect.TypeFor[Value](),
+			Embeds: []string{"Rhs"},
+			Cons: []*lang.Con{
+				{
+					Name:   "BeginValue",
+					From:   "L16",
+					GoType: reflect.TypeFor[BeginValue](),
+					Fields: []lang.Field{
+						{Name: "Init", Type: &lang.Type{Kind: lang.Slice, Elem: &lang.Type{Kind: lang.NonTerminal, Name: "Effect"}}},
+						{Name: "X", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Value"}},
+					},
+				},
+				{
+					Name:   "IfValue",
+					From:   "L16",
+					GoType: reflect.TypeFor[IfValue](),
+					Fields: []lang.Field{
+						{Name: "Cond", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Predicate"}},
+						{Name: "Then", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Value"}},
+						{Name: "Else", Type: &lang.Type{Kind: lang.NonTerminal, Name: "Value"}},
+					},
+				},
+			},
+		},
+	},
+}
+
+func init() { lang.Register(Language) }
@@ -0,0 +1,127 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L22
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mdempsky/hermes/lang"
+)
+
+func TestLookup(t *testing.T) {
+	const path = "github.com/mdempsky/hermes/example/lang/L22"
+	if Language.Path != path {
+		t.Errorf("Language.Path = %q, want %q", Language.Path, path)
+	}
+	if got := lang.Lookup(path); got != Language {
+		t.Errorf("lang.Lookup(%q) = %p, want %p", path, got, Language)
+	}
+	if got := lang.Lookup("L22"); got != nil {
+		t.Errorf("lang.Lookup(%q) = %p, want nil", "L22", got)
+	}
+	if !slices.Contains(lang.Languages(), path) {
+		t.Errorf("lang.Languages() = %v, missing %v", lang.Languages(), path)
+	}
+}
+
+func TestDescriptor(t *testing.T) {
+	if Language.Entry != "Program" || Language.Var != "Symbol" || Language.Meta != "Meta" {
+		t.Errorf("Language = {Entry: %q, Var: %q, Meta: %q}, want {Program Symbol Meta}", Language.Entry, Language.Var, Language.Meta)
+	}
+	if def := Language.Def("MRef"); def != nil {
+		t.Errorf("Def(%q) = %v, want nil for a production", "MRef", def.Name)
+	}
+
+	simple := Language.Def("SimpleExpr")
+	if simple == nil {
+		t.Fatalf("Def(%q) = nil", "SimpleExpr")
+	}
+	if simple.Kind != lang.NonTerminal || simple.From != "L15" {
+		t.Errorf("SimpleExpr is a %v from %v, want a non-terminal from L15", simple.Kind, simple.From)
+	}
+	if want := []string{"Rhs", "Value"}; !slices.Equal(simple.IsAlso, want) {
+		t.Errorf("SimpleExpr.IsAlso = %v, want %v", simple.IsAlso, want)
+	}
+	if want := []string{"Symbol"}; !slices.Equal(simple.Embeds, want) {
+		t.Errorf("SimpleExpr.Embeds = %v, want %v", simple.Embeds, want)
+	}
+
+	i := slices.IndexFunc(simple.Cons, func(con *lang.Con) bool { return con.Name == "MRef" })
+	if i < 0 {
+		t.Fatalf("SimpleExpr has no MRef production")
+	}
+	mref := simple.Cons[i]
+	if mref.From != "L22" || mref.GoType != reflect.TypeFor[MRef]() {
+		t.Errorf("MRef is %v from %v, want MRef from L22", mref.GoType, mref.From)
+	}
+	var fields []string
+	for _, f := range mref.Fields {
+		fields = append(fields, fmt.Sprintf("%v %v (%v)", f.Name, f.Type, f.Type.Kind))
+	}
+	want := []string{
+		"Ptr SimpleExpr (non-terminal)",
+		"Index *SimpleExpr (optional)",
+		"Offset int64 (int)",
+	}
+	if !slices.Equal(fields, want) {
+		t.Errorf("MRef fields = %q, want %q", fields, want)
+	}
+
+	rec := Language.Def("RecBinding")
+	if rec == nil || rec.Kind != lang.Product || len(rec.Fields) != 2 {
+		t.Fatalf("RecBinding is not a product type with two fields")
+	}
+	if f := rec.Fields[0]; f.Name != "Var" || !f.Bind || f.Type.Kind != lang.Terminal {
+		t.Errorf("RecBinding.Fields[0] = %+v, want a binding terminal Var", f)
+	}
+}
+
+// TestDescriptorTypes checks that the descriptor agrees with the Go
+// types it describes.
+func TestDescriptorTypes(t *testing.T) {
+	if !slices.IsSortedFunc(Language.Defs, func(a, b *lang.Def) int { return strings.Compare(a.Name, b.Name) }) {
+		t.Errorf("Defs are not sorted by name")
+	}
+	check := func(name string, typ reflect.Type, fields []lang.Field) {
+		if typ.Name() != name {
+			t.Errorf("%v has Go type %v", name, typ)
+			return
+		}
+		if len(fields) == 0 {
+			return
+		}
+		for _, f := range fields {
+			if _, ok := typ.FieldByName(f.Name); !ok {
+				t.Errorf("%v has no field %v", typ, f.Name)
+			}
+		}
+		if _, ok := typ.FieldByName(Language.Meta); !ok {
+			t.Errorf("%v has no metadata field %v", typ, Language.Meta)
+		}
+		if got, want := typ.NumField(), len(fields)+1; got != want {
+			t.Errorf("%v has %d fields, want %d", typ, got, want)
+		}
+	}
+	for _, def := range Language.Defs {
+		switch def.Kind {
+		case lang.Product:
+			check(def.Name, def.GoType, def.Fields)
+		case lang.NonTerminal:
+			if def.GoType.Kind() != reflect.Interface {
+				t.Errorf("non-terminal %v has Go type %v", def.Name, def.GoType)
+			}
+			for _, con := range def.Cons {
+				check(con.Name, con.GoType, con.Fields)
+				if !con.GoType.Implements(def.GoType) {
+					t.Errorf("%v does not implement %v", con.Name, def.Name)
+				}
+			}
+		}
+	}
+}
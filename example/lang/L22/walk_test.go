@@ -0,0 +1,117 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L22
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// sample returns a program that uses every kind of field: slices,
+// product types, optional pointers (both set and unset), and
+// non-node fields such as MSet.Offset.
+func sample() Program {
+	var one SimpleExpr = Int{Int: 1}
+	return Labels{
+		Bindings: []RecBinding{{
+			Var: "f",
+			Val: Lambda{
+				Params: []Symbol{"x"},
+				Locals: []Symbol{"y"},
+				Body: BeginValue{
+					Init: []Effect{MSet{Ptr: Symbol("x"), Index: &one, Offset: 8, Data: Int{Int: 2}}},
+					X:    MRef{Ptr: Symbol("x")},
+				},
+			},
+		}},
+		Entry: "f",
+	}
+}
+
+func describe(n Node) string {
+	switch n := n.(type) {
+	case Symbol:
+		return "Symbol " + string(n)
+	case Int:
+		return fmt.Sprint("Int ", n.Int)
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", n), "L22.")
+}
+
+func TestInspect(t *testing.T) {
+	var got []string
+	nils := 0
+	Inspect(sample(), func(n Node) bool {
+		if n == nil {
+			nils++
+		} else {
+			got = append(got, describe(n))
+		}
+		return true
+	})
+	want := []string{
+		"Labels",
+		"RecBinding",
+		"Symbol f",
+		"Lambda",
+		"Symbol x",
+		"Symbol y",
+		"BeginValue",
+		"MSet",
+		"Symbol x",
+		"Int 1",
+		"Int 2",
+		"MRef",
+		"Symbol x",
+		"Symbol f",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Inspect visited\n\t%v\nwant\n\t%v", got, want)
+	}
+	if nils != len(want) {
+		t.Errorf("Inspect called f(nil) %d times, want %d", nils, len(want))
+	}
+}
+
+func TestInspectPrune(t *testing.T) {
+	var got []string
+	Inspect(sample(), func(n Node) bool {
+		if n == nil {
+			return false
+		}
+		got = append(got, describe(n))
+		_, ok := n.(Lambda)
+		return !ok
+	})
+	want := []string{"Labels", "RecBinding", "Symbol f", "Lambda", "Symbol f"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Inspect visited\n\t%v\nwant\n\t%v", got, want)
+	}
+}
+
+// depthVisitor records the depth at which Walk visits each node.
+type depthVisitor struct {
+	depth  int
+	depths *[]int
+}
+
+func (v depthVisitor) Visit(n Node) Visitor {
+	if n == nil {
+		return nil
+	}
+	*v.depths = append(*v.depths, v.depth)
+	return depthVisitor{v.depth + 1, v.depths}
+}
+
+func TestWalk(t *testing.T) {
+	var got []int
+	Walk(depthVisitor{depths: &got}, sample())
+	want := []int{0, 1, 2, 2, 3, 3, 3, 4, 5, 5, 5, 4, 5, 1}
+	if !slices.Equal(got, want) {
+		t.Errorf("Walk visited depths %v, want %v", got, want)
+	}
+}
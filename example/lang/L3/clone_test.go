@@ -0,0 +1,136 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L3
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	x := mustParse(t, "(begin ((f x) (g y)) (lambda (a b) (quote (vector 1 2))))").(Begin)
+	c := Clone(x)
+	if !Equal(c, x) {
+		t.Fatalf("Clone(%v) = %v", Format(x), Format(c))
+	}
+	if !c.Meta.Same(x.Meta) {
+		t.Errorf("Clone(%v) has metadata %v, want %v", Format(x), c.Meta, x.Meta)
+	}
+
+	// Changing the copy doesn't change the original.
+	c.Init[0].(Apply).Args[0] = Symbol("z")
+	c.Body.(Lambda).Params[1] = "c"
+	c.Body.(Lambda).Body.(Quote).X.(Vector).List[0] = Int{X: 3}
+	if want := mustParse(t, "(begin ((f x) (g y)) (lambda (a b) (quote (vector 1 2))))"); !Equal(x, want) {
+		t.Errorf("changing the clone changed the original to %v", Format(x))
+	}
+
+	if c := Clone[Expr](nil); c != nil {
+		t.Errorf("Clone(nil) = %v, want nil", c)
+	}
+}
+
+func TestChildren(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{"x", nil},
+		{"(f x 1)", []string{"(symbol f)", "(symbol x)", "1"}},
+		{"(lambda (a b) (f a))", []string{"(symbol a)", "(symbol b)", "(f a)"}},
+		{"(let ([x 1] [y 2]) y)", []string{"[x 1]", "[y 2]", "(symbol y)"}},
+		{"(quote (pair 1 (nil)))", []string{"(pair 1 (nil))"}},
+	}
+	for _, tt := range tests {
+		x := mustParse(t, tt.src)
+		var got []string
+		for _, child := range Children(x) {
+			got = append(got, Format(child))
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Children(%v) = %q, want %q", tt.src, got, tt.want)
+		}
+
+		// The children are those that Walk visits.
+		var walked []string
+		depth := 0
+		Inspect(x, func(n Node) bool {
+			if n == nil {
+				depth--
+				return false
+			}
+			if depth == 1 {
+				walked = append(walked, Format(n))
+				return false
+			}
+			depth++
+			return true
+		})
+		if !slices.Equal(walked, got) {
+			t.Errorf("Walk visits the children of %v as %q, but Children returns %q", tt.src, walked, got)
+		}
+	}
+}
+
+func TestMapChildren(t *testing.T) {
+	rename := func(n Node) Node {
+		if n == Symbol("x") {
+			return Symbol("y")
+		}
+		return n
+	}
+
+	x := mustParse(t, "(begin (x (f x)) x)").(Begin)
+	got := MapChildren(x, rename).(Begin)
+	if want := mustParse(t, "(begin (y (f x)) y)"); !Equal(got, want) {
+		t.Errorf("MapChildren(%v) = %v, want %v", Format(x), Format(got), Format(want))
+	}
+	if want := mustParse(t, "(begin (x (f x)) x)"); !Equal(x, want) {
+		t.Errorf("MapChildren changed its argument to %v", Format(x))
+	}
+
+	// Unchanged slices are shared, and an unchanged node is returned
+	// as is.
+	x = mustParse(t, "(begin ((f x) z) x)").(Begin)
+	got = MapChildren(x, rename).(Begin)
+	if &got.Init[0] != &x.Init[0] {
+		t.Errorf("MapChildren(%v) copied the unchanged Init slice", Format(x))
+	}
+	if y := mustParse(t, "(f z)"); !same(MapChildren(y, rename), y) {
+		t.Errorf("MapChildren(%v) with no changes returned a copy", Format(y))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MapChildren replacing a Datum with a Symbol did not panic")
+		}
+	}()
+	MapChildren(mustParse(t, "(quote 1)"), func(Node) Node { return Symbol("x") })
+}
+
+func TestWithChildren(t *testing.T) {
+	x := mustParse(t, "(if a (f b) c)")
+	got := WithChildren(x, []Node{Symbol("c"), Symbol("b"), Symbol("a")})
+	if want := mustParse(t, "(if c b a)"); !Equal(got, want) {
+		t.Errorf("WithChildren = %v, want %v", Format(got), Format(want))
+	}
+	if got := WithChildren(x, Children(x)); !Equal(got, x) {
+		t.Errorf("WithChildren(x, Children(x)) = %v, want %v", Format(got), Format(x))
+	}
+
+	for _, children := range [][]Node{
+		{Symbol("a"), Symbol("b")},
+		{Symbol("a"), Symbol("b"), Symbol("c"), Symbol("d")},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WithChildren with %d children for %v did not panic", len(children), Format(x))
+				}
+			}()
+			WithChildren(x, children)
+		}()
+	}
+}
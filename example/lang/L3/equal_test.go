@@ -0,0 +1,80 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L3
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"x", "x", true},
+		{"x", "y", false},
+		{"car", "(symbol car)", false},
+		{"42", "(int 42)", true},
+		{"(f x 1)", "(f  x\n  1)", true}, // different spans
+		{"(f x 1)", "(f x)", false},
+		{"(f x 1)", "(f x 2)", false},
+		{"(begin (x) y)", "(begin (x y) y)", false},
+		{"(quote (vector 1 (pair 2 (nil))))", "(quote (vector 1 (pair 2 (nil))))", true},
+		{"(quote (vector 1 (pair 2 (nil))))", "(quote (vector 1 (pair 2 (true))))", false},
+		{"(lambda (x) x)", "(lambda (y) y)", false},
+		{"(let ([x 1]) x)", "(letrec ([x 1]) x)", false},
+	}
+	for _, tt := range tests {
+		a, b := mustParse(t, tt.a), mustParse(t, tt.b)
+		if got := Equal(a, b); got != tt.want {
+			t.Errorf("Equal(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := Equal(b, a); got != tt.want {
+			t.Errorf("Equal(%v, %v) = %v, want %v", tt.b, tt.a, got, tt.want)
+		}
+		if ha, hb := Hash(a), Hash(b); (ha == hb) != tt.want {
+			t.Errorf("Hash(%v) = %#x, Hash(%v) = %#x", tt.a, ha, tt.b, hb)
+		}
+	}
+}
+
+func TestEqualValues(t *testing.T) {
+	tests := []struct {
+		a, b Node
+		want bool
+	}{
+		{nil, nil, true},
+		{nil, Symbol("x"), false},
+		{Apply{Fun: Symbol("f")}, Apply{Fun: Symbol("f"), Args: []Expr{}}, true},
+		{Apply{Fun: Symbol("f")}, Apply{Args: []Expr{Symbol("f")}}, false},
+		{Binding{Var: "x", Val: Int{X: 1}}, Binding{Var: "x", Val: Int{X: 1}}, true},
+		{Binding{Var: "x", Val: Int{X: 1}}, Symbol("x"), false},
+		{True{}, False{}, false},
+	}
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.want {
+			t.Errorf("Equal(%#v, %#v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if tt.want && Hash(tt.a) != Hash(tt.b) {
+			t.Errorf("Hash(%#v) != Hash(%#v)", tt.a, tt.b)
+		}
+	}
+}
+
+func TestHashStable(t *testing.T) {
+	// Hashes can be stored, so they mustn't change between runs or
+	// releases.
+	const want = 0x5384d1633c5900ac
+	if got := Hash(mustParse(t, "(lambda (x) (f x 1))")); got != want {
+		t.Errorf("Hash = %#x, want %#x", got, uint64(want))
+	}
+}
+
+func mustParse(t *testing.T, src string) Expr {
+	t.Helper()
+	x, err := ParseExpr(src)
+	if err != nil {
+		t.Fatalf("ParseExpr(%q): %v", src, err)
+	}
+	return x
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L3
+
+import (
+	"testing"
+
+	"github.com/mdempsky/hermes/lang"
+	"github.com/mdempsky/hermes/sexpr"
+)
+
+func TestMeta(t *testing.T) {
+	x := mustParse(t, "(if c\n  (f x)\n  (quote 1))").(If)
+
+	tests := []struct {
+		n          Node
+		start, end sexpr.Pos
+	}{
+		{x, sexpr.Pos{Line: 1, Col: 1}, sexpr.Pos{Line: 3, Col: 13}},
+		{x.Then, sexpr.Pos{Line: 2, Col: 3}, sexpr.Pos{Line: 2, Col: 8}},
+		{x.Else, sexpr.Pos{Line: 3, Col: 3}, sexpr.Pos{Line: 3, Col: 12}},
+		{x.Else.(Quote).X, sexpr.Pos{Line: 3, Col: 10}, sexpr.Pos{Line: 3, Col: 11}},
+	}
+	for _, tt := range tests {
+		want := lang.Span{Start: tt.start, End: tt.end}
+		if got := MetaOf(tt.n).Span; got != want {
+			t.Errorf("MetaOf(%v).Span = %v, want %v", Format(tt.n), got, want)
+		}
+		if got := tt.n.(interface{ Pos() sexpr.Pos }).Pos(); got != tt.start {
+			t.Errorf("%v.Pos() = %v, want %v", Format(tt.n), got, tt.start)
+		}
+	}
+
+	// Terminals have no metadata.
+	if got := MetaOf(x.Cond); got.Span != (lang.Span{}) {
+		t.Errorf("MetaOf(%v) = %v, want none", Format(x.Cond), got)
+	}
+	if got := WithMeta(x.Cond, MetaOf(x)); got != x.Cond {
+		t.Errorf("WithMeta(%v) = %v, want it unchanged", Format(x.Cond), Format(got))
+	}
+
+	// Metadata doesn't affect the structure of a node.
+	m := lang.Meta[any]{Note: "checked"}
+	y := WithMeta(x, m)
+	if !y.Meta.Same(m) || !Equal(y, x) || Hash(y) != Hash(x) || Format(y) != Format(x) {
+		t.Errorf("WithMeta(%v) = %v, with metadata %v", Format(x), Format(y), y.Meta)
+	}
+	if !x.Meta.Same(MetaOf(x)) {
+		t.Errorf("WithMeta modified its argument")
+	}
+}
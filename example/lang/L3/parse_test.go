@@ -0,0 +1,96 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L3
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src  string
+		want Expr
+	}{
+		{"x", Symbol("x")},
+		{"car", PrimitiveCar},
+		{"(symbol car)", Symbol("car")},
+		{"42", Int{X: 42}},
+		{"(int 42)", Int{X: 42}},
+		{"(f 1)", Apply{Fun: Symbol("f"), Args: []Expr{Int{X: 1}}}},
+		{"(apply f 1)", Apply{Fun: Symbol("f"), Args: []Expr{Int{X: 1}}}},
+		{"((lambda (x) x) y)", Apply{Fun: Lambda{Params: []Symbol{"x"}, Body: Symbol("x")}, Args: []Expr{Symbol("y")}}},
+		{"(apply if x)", Apply{Fun: Symbol("if"), Args: []Expr{Symbol("x")}}},
+		{
+			"(letrec ([f (lambda (x) x)]) (f 1))",
+			LetRec{
+				Bindings: []Binding{{Var: "f", Val: Lambda{Params: []Symbol{"x"}, Body: Symbol("x")}}},
+				Body:     Apply{Fun: Symbol("f"), Args: []Expr{Int{X: 1}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseExpr(tt.src)
+		if err != nil {
+			t.Errorf("ParseExpr(%q): %v", tt.src, err)
+			continue
+		}
+		if !Equal(got, tt.want) {
+			t.Errorf("ParseExpr(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		x    Expr
+		want string
+	}{
+		{Symbol("x"), "(symbol x)"},
+		{Int{X: 42}, "42"},
+		{Apply{Fun: PrimitiveCar, Args: []Expr{Symbol("x")}}, "(car x)"},
+		{Apply{Fun: Symbol("car"), Args: []Expr{Symbol("x")}}, "((symbol car) x)"},
+		{Apply{Fun: Symbol("f"), Args: []Expr{Symbol("42")}}, "(f (symbol 42))"},
+		{Apply{Fun: Symbol("f"), Args: []Expr{Int{X: 1}}}, "(f 1)"},
+		{Apply{Fun: Symbol("if"), Args: []Expr{Symbol("x")}}, "(apply if x)"},
+		{
+			LetRec{
+				Bindings: []Binding{{Var: "f", Val: Lambda{Params: []Symbol{"x"}, Body: Symbol("x")}}},
+				Body:     Apply{Fun: Symbol("f"), Args: []Expr{Int{X: 1}}},
+			},
+			"(letrec ([f (lambda (x) x)]) (f 1))",
+		},
+	}
+	for _, tt := range tests {
+		got := Format(tt.x)
+		if got != tt.want {
+			t.Errorf("Format(%#v) = %q, want %q", tt.x, got, tt.want)
+		}
+		back, err := ParseExpr(got)
+		if err != nil {
+			t.Errorf("ParseExpr(%q): %v", got, err)
+		} else if !Equal(back, tt.x) {
+			t.Errorf("ParseExpr(%q) = %#v, want %#v", got, back, tt.x)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"(and x y)", "1:1: And was omitted in L2"},
+		{"(begin x)", "1:1: Begin expects 2 fields, found 1"},
+		{"(lambda x x)", "expected list"},
+		{"#f", "1:1: expected Expr, found #f"},
+		{"(lambda (#f) x)", "expected Symbol, found #f"},
+	}
+	for _, tt := range tests {
+		_, err := ParseExpr(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseExpr(%q) = %v, want error containing %q", tt.src, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L4
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mdempsky/hermes/example/lang/L3"
+)
+
+func TestBinary(t *testing.T) {
+	tests := []string{
+		"x",
+		"-42",
+		"(quote (vector 1 (pair (true) (nil))))",
+		"(primcall vector-ref v (primcall add i 1))",
+		"(let ([x (primcall add 1 2)]) (if x (quote (vector 1 (true))) (f x)))",
+		"(letrec ([f (lambda (n) (f n))] [g (lambda (n) (f n))]) (f (g (f (g 1)))))",
+	}
+	for _, src := range tests {
+		x, err := ParseExpr(src)
+		if err != nil {
+			t.Fatalf("ParseExpr(%q): %v", src, err)
+		}
+		for _, share := range []bool{false, true} {
+			data := MarshalBinary(x, share)
+			y, err := UnmarshalBinaryExpr(data)
+			if err != nil {
+				t.Errorf("UnmarshalBinaryExpr(MarshalBinary(%v, %v)): %v", src, share, err)
+				continue
+			}
+			if !Equal(x, y) {
+				t.Errorf("MarshalBinary(%v, %v) decoded as %v", src, share, Format(y))
+			}
+		}
+	}
+
+	// Sharing repeated subtrees makes the encoding smaller.
+	x, _ := ParseExpr("((lambda (n) (f n)) (lambda (n) (f n)) (lambda (n) (f n)))")
+	if plain, shared := len(MarshalBinary(x, false)), len(MarshalBinary(x, true)); shared >= plain {
+		t.Errorf("shared encoding is %d bytes, unshared %d", shared, plain)
+	}
+}
+
+func TestBinaryMismatch(t *testing.T) {
+	x, err := ParseExpr("(f x)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := MarshalBinary(x, false)
+
+	// Flip a bit of the language's hash, which follows the magic,
+	// the version, and the import path of the language's package.
+	changed := []byte(string(data))
+	changed[len("hermes")+1+1+len(Language.Path)] ^= 1
+
+	y, err := L3.ParseExpr("(f x)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"other language", L3.MarshalBinary(y, false), "encoding is of " + L3.Language.Path},
+		{"other definition", changed, "different definition of L4"},
+		{"truncated", data[:len(data)-1], "L4: decoding"},
+	}
+	for _, tt := range tests {
+		if _, err := UnmarshalBinaryExpr(tt.data); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%v: UnmarshalBinaryExpr: %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L4
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mdempsky/hermes/example/lang/L3"
+	"github.com/mdempsky/hermes/lang"
+)
+
+const dotHeader = `digraph "L4" {
+	node [shape=box, fontname="Helvetica"];
+	edge [fontname="Helvetica", fontsize=10];
+`
+
+func TestWriteDOT(t *testing.T) {
+	x, err := ParseExpr("(if c (primcall car p) (quote 1))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev, err := L3.ParseExpr("(if c (car p) (quote 1))")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		x    Node
+		opts *lang.DOTOptions
+		want string
+	}{
+		{
+			"tree", x, nil,
+			`	n0 [label="If\lCond: c\l"];
+	n1 [label="PrimCall\lPrim: car\lArgs[0]: p\l"];
+	n0 -> n1 [label="Then"];
+	n2 [label="Quote\l"];
+	n3 [label="Int\lX: 1\l"];
+	n2 -> n3 [label="X"];
+	n0 -> n2 [label="Else"];
+`,
+		},
+		{
+			// Only the primitive call, which was an application in L3,
+			// is highlighted.
+			"changes", x, &lang.DOTOptions{Prev: prev, PrevLanguage: L3.Language},
+			`	n0 [label="If\lCond: c\l"];
+	n1 [label="PrimCall\lPrim: car\lArgs[0]: p\l", style=filled, fillcolor="#ffd966"];
+	n0 -> n1 [label="Then"];
+	n2 [label="Quote\l"];
+	n3 [label="Int\lX: 1\l"];
+	n2 -> n3 [label="X"];
+	n0 -> n2 [label="Else"];
+`,
+		},
+		{
+			"terminals", Lambda{Params: []Symbol{"a", `b"c`}, Body: Symbol("a")}, nil,
+			`	n0 [label="Lambda\lParams: (a b\"c)\lBody: a\l"];
+`,
+		},
+		{
+			"product", Binding{Var: "x", Val: Apply{Fun: Symbol("f")}}, nil,
+			`	n0 [label="Binding\lVar: x\l"];
+	n1 [label="Apply\lFun: f\l"];
+	n0 -> n1 [label="Val"];
+`,
+		},
+		{"nil", nil, nil, ""},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := WriteDOT(&b, tt.x, tt.opts); err != nil {
+			t.Errorf("%v: WriteDOT: %v", tt.name, err)
+			continue
+		}
+		if want := dotHeader + tt.want + "}\n"; b.String() != want {
+			t.Errorf("%v: WriteDOT wrote\n%v\nwant\n%v", tt.name, b.String(), want)
+		}
+	}
+}
+
+func TestWriteDOTErrors(t *testing.T) {
+	x, err := L3.ParseExpr("(car p)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := lang.WriteDOT(&b, Language, x, nil); err == nil || !strings.Contains(err.Error(), "is not a value of L4") {
+		t.Errorf("WriteDOT of an L3 value as L4: %v", err)
+	}
+	if err := WriteDOT(&b, Symbol("x"), &lang.DOTOptions{Prev: x}); err == nil || !strings.Contains(err.Error(), "no language") {
+		t.Errorf("WriteDOT with a Prev but no PrevLanguage: %v", err)
+	}
+}
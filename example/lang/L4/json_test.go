@@ -0,0 +1,94 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L4
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []string{
+		"x",
+		"42",
+		"(f x 1)",
+		"(quote (vector 1 (pair (true) (nil))))",
+		"(primcall vector-ref v (primcall add i 1))",
+		"(lambda (x y) (begin ((set x y)) x))",
+		"(let ([x (primcall add 1 2)]) (if x (quote (vector 1 (true))) (f x)))",
+		"(letrec ([f (lambda (n) (f n))] [g (lambda () (false))]) (f (g)))",
+	}
+	for _, src := range tests {
+		x, err := ParseExpr(src)
+		if err != nil {
+			t.Fatalf("ParseExpr(%q): %v", src, err)
+		}
+		data, err := MarshalJSON(x)
+		if err != nil {
+			t.Errorf("MarshalJSON(%v): %v", src, err)
+			continue
+		}
+		y, err := UnmarshalJSONExpr(data)
+		if err != nil {
+			t.Errorf("UnmarshalJSONExpr(%s): %v", data, err)
+			continue
+		}
+		if !Equal(x, y) {
+			t.Errorf("%v encoded as %s and decoded as %v", src, data, Format(y))
+		}
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	x, err := ParseExpr("(let ([x (primcall add 1 2)]) (f x))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"type":"Let","Bindings":[{"Var":"x","Val":{"type":"PrimCall","Prim":"add","Args":[{"type":"Int","X":1},{"type":"Int","X":2}]}}],` +
+		`"Body":{"type":"Apply","Fun":{"type":"Symbol","value":"f"},"Args":[{"type":"Symbol","value":"x"}]}}`
+	data, err := MarshalJSON(x)
+	if err != nil || string(data) != want {
+		t.Errorf("MarshalJSON = %s, %v; want %s", data, err, want)
+	}
+
+	// encoding/json uses the same format, through the methods.
+	var b Binding
+	if err := json.Unmarshal([]byte(`{"Var":"y","Val":{"type":"Symbol","value":"x"}}`), &b); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if want := (Binding{Var: "y", Val: Symbol("x")}); !Equal(b, want) {
+		t.Errorf("json.Unmarshal = %#v, want %#v", b, want)
+	}
+	data, err = json.Marshal(b)
+	if want := `{"Var":"y","Val":{"type":"Symbol","value":"x"}}`; err != nil || string(data) != want {
+		t.Errorf("json.Marshal = %s, %v; want %s", data, err, want)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		data, err string
+	}{
+		{`{`, "expected Expr object"},
+		{`[]`, "expected Expr object"},
+		{`{"type":"Nope"}`, `"Nope" does not belong to Expr`},
+		{`{"type":"If","Cond":1}`, "expected Expr object, found 1"},
+		{`{"type":"PrimCall","Prim":"nope","Args":[]}`, `expected Primitive, found "nope"`},
+		{`{"type":"Symbol","value":""}`, `expected Symbol, found ""`},
+		{`{"type":"Let","Bindings":[{"Var":"x"}],"Body":{"type":"Symbol","value":"x"}}`, `missing "Val" member`},
+		{`{"type":"Quote","X":{"type":"Symbol","value":"x"}}`, `"Symbol" does not belong to Datum`},
+	}
+	for _, tt := range tests {
+		x, err := UnmarshalJSONExpr([]byte(tt.data))
+		if err == nil {
+			t.Errorf("UnmarshalJSONExpr(%s) = %v, want error", tt.data, Format(x))
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("UnmarshalJSONExpr(%s): %v, want %q", tt.data, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L4
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mdempsky/hermes/lang"
+)
+
+func TestPrim(t *testing.T) {
+	want := lang.Prim{Name: "cons", Code: 7, Arity: 2, Class: lang.Value, Pure: true, Alloc: true}
+	if p := PrimitiveCons.Prim(); p == nil || *p != want {
+		t.Errorf("PrimitiveCons.Prim() = %v, want %v", p, want)
+	}
+	if p := Primitive(999).Prim(); p != nil {
+		t.Errorf("Primitive(999).Prim() = %v, want nil", p)
+	}
+	if got := Primitive(999).String(); got != "Primitive(999)" {
+		t.Errorf("Primitive(999).String() = %q, want %q", got, "Primitive(999)")
+	}
+}
+
+func TestLookupPrimitive(t *testing.T) {
+	tests := []struct {
+		name string
+		want Primitive
+		ok   bool
+	}{
+		{"cons", PrimitiveCons, true},
+		{"vector-set", PrimitiveVectorSet, true},
+		{"void", PrimitiveVoid, true},
+		{"VectorSet", 0, false},
+		{"frobnicate", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := LookupPrimitive(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("LookupPrimitive(%q) = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParsePrimitive(t *testing.T) {
+	got, err := ParseExpr("(primcall vector-set v i x)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call, ok := got.(PrimCall); !ok || call.Prim != PrimitiveVectorSet {
+		t.Errorf("ParseExpr = %#v, want a PrimCall of PrimitiveVectorSet", got)
+	}
+
+	_, err = ParseExpr("(primcall frobnicate x)")
+	if want := "1:11: expected Primitive, found frobnicate"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ParseExpr of an unknown primitive = %v, want error containing %q", err, want)
+	}
+}
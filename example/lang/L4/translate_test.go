@@ -0,0 +1,90 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package L4
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mdempsky/hermes/example/lang/L3"
+	"github.com/mdempsky/hermes/lang"
+)
+
+// primCalls is a pass from L3 into L4, which turns applications of
+// primitives into primcalls. It leaves every other node to be
+// translated 1:1.
+var primCalls = Translator{
+	Pass: func(t *Translator, x L3.Node) (Node, bool) {
+		n, ok := x.(L3.Apply)
+		if !ok {
+			return nil, false
+		}
+		prim, ok := n.Fun.(L3.Primitive)
+		if !ok {
+			return nil, false
+		}
+		args := make([]Expr, len(n.Args))
+		for i, arg := range n.Args {
+			args[i] = TranslateAs[Expr](t, arg)
+		}
+		return WithMeta(PrimCall{Prim: TranslateAs[Primitive](t, prim), Args: args}, L3.MetaOf(n)), true
+	},
+}
+
+func TestTranslate(t *testing.T) {
+	src, err := L3.ParseExpr("(if c\n  (car x)\n  (let ([f (quote 1)]) (f y)))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := TranslateAs[Expr](&primCalls, src)
+
+	want, err := ParseExpr("(if c (primcall car x) (let ([f (quote 1)]) (f y)))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(got, want) {
+		t.Fatalf("translated %v to %v, want %v", L3.Format(src), Format(got), Format(want))
+	}
+
+	// The spans of the nodes survive the pass, both where it
+	// translates them 1:1 and where it carries them along itself.
+	x, y := src.(L3.If), got.(If)
+	let0, let := x.Else.(L3.Let), y.Else.(Let)
+	tests := []struct {
+		name string
+		src  lang.Meta[any]
+		got  lang.Meta[any]
+	}{
+		{"if", x.Meta, y.Meta},
+		{"primcall", L3.MetaOf(x.Then), MetaOf(y.Then)},
+		{"let", let0.Meta, let.Meta},
+		{"binding", let0.Bindings[0].Meta, let.Bindings[0].Meta},
+		{"quote", L3.MetaOf(let0.Bindings[0].Val), MetaOf(let.Bindings[0].Val)},
+		{"apply", L3.MetaOf(let0.Body), MetaOf(let.Body)},
+	}
+	for _, tt := range tests {
+		if tt.src.Span == (lang.Span{}) {
+			t.Errorf("%v has no span", tt.name)
+		}
+		if !tt.got.Same(tt.src) {
+			t.Errorf("translated %v has metadata %v, want %v", tt.name, tt.got, tt.src)
+		}
+	}
+	if y.Pos() != x.Pos() {
+		t.Errorf("translated if is at %v, want %v", y.Pos(), x.Pos())
+	}
+}
+
+func TestTranslateMismatch(t *testing.T) {
+	// A primitive is an expression in L3, but not in L4, so it has no
+	// 1:1 translation as one.
+	defer func() {
+		const want = "cannot use L4.Primitive, the translation of L3.Primitive, as L4.Expr"
+		if r := recover(); r == nil || !strings.Contains(r.(string), want) {
+			t.Errorf("TranslateAs panicked with %v, want %q", r, want)
+		}
+	}()
+	TranslateAs[Expr](new(Translator), L3.Expr(L3.PrimitiveCar))
+}
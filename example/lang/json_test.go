@@ -0,0 +1,235 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lang_test
+
+import (
+	"path"
+	"slices"
+	"testing"
+
+	"github.com/mdempsky/hermes/example/lang/L1"
+	"github.com/mdempsky/hermes/example/lang/L10"
+	"github.com/mdempsky/hermes/example/lang/L11"
+	"github.com/mdempsky/hermes/example/lang/L12"
+	"github.com/mdempsky/hermes/example/lang/L13"
+	"github.com/mdempsky/hermes/example/lang/L14"
+	"github.com/mdempsky/hermes/example/lang/L15"
+	"github.com/mdempsky/hermes/example/lang/L16"
+	"github.com/mdempsky/hermes/example/lang/L17"
+	"github.com/mdempsky/hermes/example/lang/L18"
+	"github.com/mdempsky/hermes/example/lang/L19"
+	"github.com/mdempsky/hermes/example/lang/L2"
+	"github.com/mdempsky/hermes/example/lang/L21"
+	"github.com/mdempsky/hermes/example/lang/L22"
+	"github.com/mdempsky/hermes/example/lang/L3"
+	"github.com/mdempsky/hermes/example/lang/L4"
+	"github.com/mdempsky/hermes/example/lang/L5"
+	"github.com/mdempsky/hermes/example/lang/L6"
+	"github.com/mdempsky/hermes/example/lang/L7"
+	"github.com/mdempsky/hermes/example/lang/L8"
+	"github.com/mdempsky/hermes/example/lang/L9"
+	"github.com/mdempsky/hermes/example/lang/Lsrc"
+	"github.com/mdempsky/hermes/lang"
+)
+
+// roundTrip returns a test that parses each of srcs, and checks that
+// encoding the result with marshal and decoding it with unmarshal
+// gives back an equal tree.
+func roundTrip[T, N any](parse func(string) (T, error), marshal func(N) ([]byte, error), unmarshal func([]byte) (T, error), equal func(N, N) bool, srcs ...string) func(*testing.T) {
+	return func(t *testing.T) {
+		for _, src := range srcs {
+			x, err := parse(src)
+			if err != nil {
+				t.Errorf("parsing %q: %v", src, err)
+				continue
+			}
+			data, err := marshal(any(x).(N))
+			if err != nil {
+				t.Errorf("MarshalJSON(%v): %v", src, err)
+				continue
+			}
+			y, err := unmarshal(data)
+			if err != nil {
+				t.Errorf("unmarshaling %s: %v", data, err)
+				continue
+			}
+			if !equal(any(x).(N), any(y).(N)) {
+				t.Errorf("%v encoded as %s and decoded as %#v", src, data, y)
+			}
+		}
+	}
+}
+
+// TestJSON round-trips the entries of every example language, and
+// their product types, through JSON.
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		lang  string
+		tests map[string]func(*testing.T)
+	}{
+		{"Lsrc", map[string]func(*testing.T){
+			"Expr": roundTrip(Lsrc.ParseExpr, Lsrc.MarshalJSON, Lsrc.UnmarshalJSONExpr, Lsrc.Equal,
+				"(let ([x (quote (pair 1 (vector (true) (nil))))]) (x) (if (and x (not (or))) (car x) (begin ((ifthen x (set x 1))) (lambda (y) () y))))",
+				"(letrec ([f (lambda (n) () (f n))]) () (f (false)))",
+			),
+			"Binding": roundTrip(Lsrc.ParseBinding, Lsrc.MarshalJSON, Lsrc.UnmarshalJSONBinding, Lsrc.Equal,
+				"[x (quote (vector))]",
+			),
+		}},
+		{"L1", map[string]func(*testing.T){
+			"Expr": roundTrip(L1.ParseExpr, L1.MarshalJSON, L1.UnmarshalJSONExpr, L1.Equal,
+				"(let ([x (quote (pair 1 (vector (true) (nil))))]) (x) (if (and x (not (or))) (void) (begin ((set x 1)) (lambda (y) () y))))",
+			),
+		}},
+		{"L2", map[string]func(*testing.T){
+			"Expr": roundTrip(L2.ParseExpr, L2.MarshalJSON, L2.UnmarshalJSONExpr, L2.Equal,
+				"(let ([x (quote (pair 1 (vector (true) (nil))))]) (x) (if x (car x) (begin ((set x 1)) (lambda (y) () y))))",
+			),
+		}},
+		{"L3", map[string]func(*testing.T){
+			"Expr": roundTrip(L3.ParseExpr, L3.MarshalJSON, L3.UnmarshalJSONExpr, L3.Equal,
+				"(let ([x (quote (pair 1 (vector (true) (nil))))]) (if x (car x) (begin ((set x 1)) (lambda (y) y))))",
+				"(letrec ([f (lambda (n) (f n))]) (f (false)))",
+			),
+		}},
+		{"L4", map[string]func(*testing.T){
+			"Expr": roundTrip(L4.ParseExpr, L4.MarshalJSON, L4.UnmarshalJSONExpr, L4.Equal,
+				"(let ([x (quote (pair 1 (vector (true) (nil))))]) (if x (primcall car x) (begin ((set x 1)) (lambda (y) y))))",
+			),
+			"Binding": roundTrip(L4.ParseBinding, L4.MarshalJSON, L4.UnmarshalJSONBinding, L4.Equal,
+				"[x (primcall cons 1 (quote (nil)))]",
+			),
+		}},
+		{"L5", map[string]func(*testing.T){
+			"Expr": roundTrip(L5.ParseExpr, L5.MarshalJSON, L5.UnmarshalJSONExpr, L5.Equal,
+				"(let ([x (quote (pair 1 (vector (true) (nil))))]) (if x (primcall car x) (begin ((set x (quote 1))) (lambda (y) y))))",
+				"(letrec ([f (lambda (n) (f n))]) (f (quote (false))))",
+			),
+		}},
+		{"L6", map[string]func(*testing.T){
+			"Expr": roundTrip(L6.ParseExpr, L6.MarshalJSON, L6.UnmarshalJSONExpr, L6.Equal,
+				"(let ([x (quote 1)]) (if x (primcall cons x (quote (nil))) (begin ((set x (quote (true)))) (lambda (y) y))))",
+			),
+		}},
+		{"L7", map[string]func(*testing.T){
+			"Expr": roundTrip(L7.ParseExpr, L7.MarshalJSON, L7.UnmarshalJSONExpr, L7.Equal,
+				"(let ([x (quote 1)]) [(x) (if x (primcall cons x (quote (nil))) (begin ((set x (quote (true)))) (lambda (y) [() y])))])",
+				"(letrec ([f (lambda (n) [() (f n)])]) [() (f (quote (false)))])",
+			),
+		}},
+		{"L8", map[string]func(*testing.T){
+			"Expr": roundTrip(L8.ParseExpr, L8.MarshalJSON, L8.UnmarshalJSONExpr, L8.Equal,
+				"(let ([x (quote 1)]) [(x) (if x (primcall cons x (quote (nil))) (begin ((set x (quote (true)))) (lambda (y) [() y])))])",
+				"(letrec ([f (lambda (n) [() (f n)])]) (f (quote (false))))",
+			),
+			"RecBinding": roundTrip(L8.ParseRecBinding, L8.MarshalJSON, L8.UnmarshalJSONRecBinding, L8.Equal,
+				"[f (lambda (n) [(n) (set n (quote 1))])]",
+			),
+		}},
+		{"L9", map[string]func(*testing.T){
+			"Expr": roundTrip(L9.ParseExpr, L9.MarshalJSON, L9.UnmarshalJSONExpr, L9.Equal,
+				"(let ([x (quote 1)]) [(x) (if x (primcall cons x (quote (nil))) (begin ((set x (quote (true)))) x))])",
+				"(letrec ([f (lambda (n) [() (f n)])]) (f (quote (false))))",
+			),
+		}},
+		{"L10", map[string]func(*testing.T){
+			"Expr": roundTrip(L10.ParseExpr, L10.MarshalJSON, L10.UnmarshalJSONExpr, L10.Equal,
+				"(let ([x (quote 1)]) (if x (primcall cons x (quote (nil))) (begin ((primcall set-box x (quote (true)))) x)))",
+				"(letrec ([f (lambda (n) (f n))]) (f (quote (false))))",
+			),
+		}},
+		{"L11", map[string]func(*testing.T){
+			"Expr": roundTrip(L11.ParseExpr, L11.MarshalJSON, L11.UnmarshalJSONExpr, L11.Equal,
+				"(letrec ([f (lambda (n) (free (f) (f n)))]) (f (quote (false))))",
+			),
+		}},
+		{"L12", map[string]func(*testing.T){
+			"Expr": roundTrip(L12.ParseExpr, L12.MarshalJSON, L12.UnmarshalJSONExpr, L12.Equal,
+				"(closures ([f f$1 f]) (labels ([f$1 (lambda (cp n) (free (f) (f n)))]) (f (quote (false)))))",
+			),
+			"Closure": roundTrip(L12.ParseClosure, L12.MarshalJSON, L12.UnmarshalJSONClosure, L12.Equal,
+				"[f f$1]",
+				"[f f$1 g h]",
+			),
+		}},
+		{"L13", map[string]func(*testing.T){
+			"Expr": roundTrip(L13.ParseExpr, L13.MarshalJSON, L13.UnmarshalJSONExpr, L13.Equal,
+				"(labels ([f (lambda (cp n) (f n))]) (let ([x (label f)]) (f x)))",
+			),
+		}},
+		{"L14", map[string]func(*testing.T){
+			"Program": roundTrip(L14.ParseProgram, L14.MarshalJSON, L14.UnmarshalJSONProgram, L14.Equal,
+				"(labels ([f (lambda (cp n) (if n (begin ((primcall car n)) (label f)) (let ([x (quote 1)]) (f x))))]) f)",
+				"(labels () f)",
+			),
+		}},
+		{"L15", map[string]func(*testing.T){
+			"Program": roundTrip(L15.ParseProgram, L15.MarshalJSON, L15.UnmarshalJSONProgram, L15.Equal,
+				"(labels ([f (lambda (cp n) (if n (begin ((primcall car n)) (label f)) (let ([x (quote 1)]) (f x (quote (nil))))))]) f)",
+			),
+		}},
+		{"L16", map[string]func(*testing.T){
+			"Program": roundTrip(L16.ParseProgram, L16.MarshalJSON, L16.UnmarshalJSONProgram, L16.Equal,
+				"(labels ([f (lambda (cp n) (ifvalue (primpred eq n (quote 1)) (beginvalue ((primeffect set-box n (quote 1)) (ifeffect (true) (nop) (applyeffect f n))) (primvalue car n)) (letvalue ([x (quote (nil))]) (applyvalue f x))))]) f)",
+				"(labels ([f (lambda () (ifvalue (ifpred (beginpred () (false)) (letpred ([x (quote 1)]) (true)) (true)) (beginvalue ((leteffect () (nop))) (label f)) (label f)))]) f)",
+			),
+			"Binding": roundTrip(L16.ParseBinding, L16.MarshalJSON, L16.UnmarshalJSONBinding, L16.Equal,
+				"[x (primvalue cons (quote 1) (quote (nil)))]",
+			),
+		}},
+		{"L17", map[string]func(*testing.T){
+			"Program": roundTrip(L17.ParseProgram, L17.MarshalJSON, L17.UnmarshalJSONProgram, L17.Equal,
+				"(labels ([f (lambda (cp n) (letvalue ([x (alloc 2 (quote 3))]) (ifvalue (primpred eq n x) (applyvalue f x) (primvalue car n))))]) f)",
+			),
+		}},
+		{"L18", map[string]func(*testing.T){
+			"Program": roundTrip(L18.ParseProgram, L18.MarshalJSON, L18.UnmarshalJSONProgram, L18.Equal,
+				"(labels ([f (lambda (cp n) (x) (beginvalue ((set x (alloc 2 (quote 3))) (ifeffect (primpred eq n x) (nop) (primeffect set-box x n))) (applyvalue f x)))]) f)",
+			),
+			"RecBinding": roundTrip(L18.ParseRecBinding, L18.MarshalJSON, L18.UnmarshalJSONRecBinding, L18.Equal,
+				"[f (lambda () () (quote (nil)))]",
+			),
+		}},
+		{"L19", map[string]func(*testing.T){
+			"Program": roundTrip(L19.ParseProgram, L19.MarshalJSON, L19.UnmarshalJSONProgram, L19.Equal,
+				"(labels ([f (lambda (cp n) (x) (beginvalue ((set x (primvalue car n)) (set x (applyvalue f x))) (ifvalue (true) x (alloc 2 (quote 3)))))]) f)",
+			),
+		}},
+		{"L21", map[string]func(*testing.T){
+			"Program": roundTrip(L21.ParseProgram, L21.MarshalJSON, L21.UnmarshalJSONProgram, L21.Equal,
+				"(labels ([f (lambda (cp n) (x) (beginvalue ((set x (primvalue car n)) (set x (applyvalue f 1 (label f)))) (ifvalue (primpred eq x 0) x (alloc 2 3))))]) f)",
+			),
+		}},
+		{"L22", map[string]func(*testing.T){
+			"Program": roundTrip(L22.ParseProgram, L22.MarshalJSON, L22.UnmarshalJSONProgram, L22.Equal,
+				"(labels ([f (lambda (cp n) (x) (beginvalue ((set x (add n 1)) (mset x #f 8 n) (mset x n 8 (mref x #f 0))) (ifvalue (lss x 0) (mref x n 16) (alloc 2 3))))]) f)",
+				"(labels ([f (lambda () () (ifvalue (ifpred (eql 1 2) (leq 1 2) (beginpred ((nop)) (true))) (subtract (multiple 2 3) (divide 6 (shiftleft 1 (shiftright 4 (logicaland 7 1))))) (label f)))]) f)",
+			),
+			"SimpleExpr": roundTrip(L22.ParseSimpleExpr, L22.MarshalJSON, L22.UnmarshalJSONSimpleExpr, L22.Equal,
+				"(mref x #f 8)",
+				"(mref x (add y 1) 8)",
+			),
+			"Effect": roundTrip(L22.ParseEffect, L22.MarshalJSON, L22.UnmarshalJSONEffect, L22.Equal,
+				"(mset x #f 8 y)",
+				"(mset x (mref y #f 0) 8 y)",
+			),
+		}},
+	}
+
+	var langs []string
+	for _, tt := range tests {
+		langs = append(langs, tt.lang)
+		for name, test := range tt.tests {
+			t.Run(tt.lang+"/"+name, test)
+		}
+	}
+
+	// Every example language is covered.
+	for _, p := range lang.Languages() {
+		if !slices.Contains(langs, path.Base(p)) {
+			t.Errorf("TestJSON does not cover %v", p)
+		}
+	}
+}
@@ -61,8 +61,6 @@ import (
 	"github.com/mdempsky/hermes/example/lang/L9"
 )
 
-const Unbox = 100
-
 func Entry(e L9.Expr) L10.Expr { return nil }
 
 type env struct {
@@ -75,7 +73,7 @@ func (env) Expr(e L9.Expr) L10.Expr {
 
 func (env env) Symbol(x L10.Symbol) L10.Expr {
 	if box, ok := env.box[x]; ok {
-		return L10.PrimCall{Prim: Unbox, Args: []L10.Expr{box}}
+		return L10.PrimCall{Prim: L10.PrimitiveUnbox, Args: []L10.Expr{box}}
 	}
 	return x
 }
@@ -30,12 +30,6 @@ import (
 	"github.com/mdempsky/hermes/example/lang/L6"
 )
 
-const (
-	Cons = 100 + iota
-	MakeVector
-	VectorSet
-)
-
 type expr struct {
 	x    L6.Expr
 	data builtin.List[L6.Binding]
@@ -73,7 +67,7 @@ func Datum(e L5.Datum) expr {
 
 func Pair(car, cdr L6.Expr) expr {
 	return expr{
-		x: L6.PrimCall{Prim: Cons, Args: []L6.Expr{car, cdr}},
+		x: L6.PrimCall{Prim: L6.PrimitiveCons, Args: []L6.Expr{car, cdr}},
 	}
 }
 
@@ -81,10 +75,10 @@ func Vector(elems []L6.Expr) expr {
 	tmp := builtin.Fresh[L6.Symbol]()
 	return expr{
 		x: L6.Let{
-			Bindings: []L6.Binding{{Var: tmp, Val: L6.PrimCall{Prim: MakeVector, Args: []L6.Expr{L6.Quote{X: L6.Int{len(elems)}}}}}},
+			Bindings: []L6.Binding{{Var: tmp, Val: L6.PrimCall{Prim: L6.PrimitiveMakeVector, Args: []L6.Expr{L6.Quote{X: L6.Int{X: len(elems)}}}}}},
 			Body: L6.Begin{
 				Init: builtin.MapIndex(elems, func(i int, elem L6.Expr) L6.Expr {
-					return L6.PrimCall{Prim: VectorSet, Args: []L6.Expr{tmp, L6.Quote{L6.Int{i}}, elem}}
+					return L6.PrimCall{Prim: L6.PrimitiveVectorSet, Args: []L6.Expr{tmp, L6.Quote{X: L6.Int{X: i}}, elem}}
 				}),
 				Body: tmp,
 			},
@@ -21,10 +21,8 @@ import (
 	"github.com/mdempsky/hermes/example/lang/Lsrc"
 )
 
-const VOID = 42
-
 func Entry(Lsrc.Expr) L1.Expr { return nil }
 
 func IfThen(Cond, Then L1.Expr) L1.Expr {
-	return L1.If{Cond: Cond, Then: Then, Else: L1.Primitive(VOID)}
+	return L1.If{Cond: Cond, Then: Then, Else: L1.PrimitiveVoid}
 }
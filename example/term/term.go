@@ -0,0 +1,18 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package term defines the Go representations of the example
+// languages' terminals that aren't plain integers.
+package term
+
+import "strings"
+
+// A Symbol is the name of a variable or label.
+type Symbol string
+
+// Valid reports whether s is a valid symbol: a non-empty s-expression
+// atom, other than "#f", which is reserved for absent values.
+func (s Symbol) Valid() bool {
+	return s != "" && s != "#f" && !strings.ContainsAny(string(s), " \t\n\r()[];")
+}
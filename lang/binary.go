@@ -0,0 +1,176 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lang
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// The binary encoding of a value of a language, as written by the
+// MarshalBinary functions generated by mklang, starts with a header:
+//
+//	magic    "hermes"
+//	version  uvarint, BinaryVersion
+//	path     string, the import path of the language's package
+//	hash     8 bytes, little-endian, the language's Hash
+//	flags    uvarint, 1 if subtrees are shared
+//
+// where strings are a uvarint length followed by the bytes. The
+// generated code defines the rest.
+const binaryMagic = "hermes"
+
+// BinaryVersion is the version of the binary encoding's header.
+const BinaryVersion = 1
+
+// An Encoder writes binary encodings, for the MarshalBinary functions
+// generated by mklang.
+type Encoder struct {
+	buf []byte
+}
+
+// NewEncoder returns an Encoder that has written the header for a
+// value of l, recording whether subtrees are shared.
+func NewEncoder(l *Language, shared bool) *Encoder {
+	e := &Encoder{buf: []byte(binaryMagic)}
+	e.Uint(BinaryVersion)
+	e.String(l.Path)
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, l.Hash())
+	flags := uint64(0)
+	if shared {
+		flags = 1
+	}
+	e.Uint(flags)
+	return e
+}
+
+// Uint writes x as a uvarint.
+func (e *Encoder) Uint(x uint64) {
+	e.buf = binary.AppendUvarint(e.buf, x)
+}
+
+// Int writes x as a varint.
+func (e *Encoder) Int(x int64) {
+	e.buf = binary.AppendVarint(e.buf, x)
+}
+
+// String writes s, prefixed by its length.
+func (e *Encoder) String(s string) {
+	e.Uint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// Bytes returns the encoding written so far.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// A Decoder reads binary encodings, for the UnmarshalBinary functions
+// generated by mklang. After an error, which Err reports, its methods
+// return zero values, so that decoding stops soon after.
+type Decoder struct {
+	data   []byte
+	name   string
+	shared bool
+	err    error
+}
+
+// NewDecoder returns a Decoder for data, which must be the encoding of
+// a value of l, after reading the header. It's an error if the header
+// records another language, or a different definition of l.
+func NewDecoder(l *Language, data []byte) *Decoder {
+	d := &Decoder{data: data, name: l.Name}
+	if len(data) < len(binaryMagic) || string(data[:len(binaryMagic)]) != binaryMagic {
+		d.Failf("not a binary encoding")
+		return d
+	}
+	d.data = data[len(binaryMagic):]
+	if v := d.Uint(); d.err == nil && v != BinaryVersion {
+		d.Failf("unsupported version %d", v)
+	}
+	if path := d.String(); d.err == nil && path != l.Path {
+		d.Failf("encoding is of %v", path)
+	}
+	if d.err != nil {
+		return d
+	}
+	if len(d.data) < 8 {
+		d.Failf("truncated header")
+		return d
+	}
+	if hash := binary.LittleEndian.Uint64(d.data); hash != l.Hash() {
+		d.Failf("encoding is of a different definition of %v (hash %016x, want %016x)", l.Name, hash, l.Hash())
+		return d
+	}
+	d.data = d.data[8:]
+	d.shared = d.Uint() == 1
+	return d
+}
+
+// Shared reports whether the encoding shares subtrees.
+func (d *Decoder) Shared() bool {
+	return d.shared
+}
+
+// Failf records an error, unless there is one already.
+func (d *Decoder) Failf(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%v: decoding: %v", d.name, fmt.Sprintf(format, args...))
+		d.data = nil
+	}
+}
+
+// Err returns the first error, or an error if any data remains.
+func (d *Decoder) Err() error {
+	if d.err == nil && len(d.data) != 0 {
+		d.Failf("%d bytes of trailing data", len(d.data))
+	}
+	return d.err
+}
+
+// Uint reads a uvarint.
+func (d *Decoder) Uint() uint64 {
+	x, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.Failf("truncated or overlong uvarint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return x
+}
+
+// Int reads a varint.
+func (d *Decoder) Int() int64 {
+	x, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.Failf("truncated or overlong varint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return x
+}
+
+// Len reads a length, which can be no more than the number of bytes
+// that remain, since each element takes at least one.
+func (d *Decoder) Len() int {
+	n := d.Uint()
+	if n > uint64(len(d.data)) {
+		d.Failf("length %d exceeds the remaining data", n)
+		return 0
+	}
+	return int(n)
+}
+
+// String reads a string written by Encoder.String.
+func (d *Decoder) String() string {
+	n := d.Uint()
+	if n > uint64(len(d.data)) {
+		d.Failf("truncated string")
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lang
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newLanguage returns a language named L, in package example.com/L,
+// with a single terminal, Symbol, represented by the Go type of S.
+func newLanguage[S any]() *Language {
+	return &Language{
+		Name: "L",
+		Path: "example.com/L",
+		Defs: []*Def{{Name: "Symbol", Kind: Terminal, GoType: reflect.TypeFor[S]()}},
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	type Symbol string
+	l := newLanguage[Symbol]()
+
+	e := NewEncoder(l, true)
+	e.String("x")
+	d := NewDecoder(newLanguage[Symbol](), e.Bytes())
+	if s := d.String(); s != "x" || !d.Shared() || d.Err() != nil {
+		t.Errorf("decoded %q, shared %v, error %v; want %q, true, nil", s, d.Shared(), d.Err(), "x")
+	}
+
+	// Encoders and decoders must agree on the whole header.
+	header := NewEncoder(l, false).Bytes()
+	version := len(binaryMagic)
+	tests := []struct {
+		name string
+		l    *Language
+		data []byte
+		err  string
+	}{
+		{"magic", l, []byte("hermit"), "not a binary encoding"},
+		{"version", l, append(append(header[:version:version], BinaryVersion+1), header[version+1:]...), "unsupported version 2"},
+		{"path", &Language{Name: "L", Path: "example.org/L", Defs: l.Defs}, header, "encoding is of example.com/L"},
+		{"truncated", l, header[:version+2+len(l.Path)], "truncated header"},
+		{"terminal", newLanguage[int](), header, "different definition of L"},
+		{"trailing", l, append(header, 0), "1 bytes of trailing data"},
+	}
+	for _, tt := range tests {
+		d := NewDecoder(tt.l, tt.data)
+		if err := d.Err(); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%v: NewDecoder: %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestHashTerminals(t *testing.T) {
+	type Symbol string
+	type Label string
+	tests := []struct {
+		a, b *Language
+		want bool
+	}{
+		{newLanguage[Symbol](), newLanguage[Symbol](), true},
+		{newLanguage[Symbol](), newLanguage[Label](), false},
+		{newLanguage[Symbol](), newLanguage[int](), false},
+		{newLanguage[int](), newLanguage[uint](), false},
+	}
+	for _, tt := range tests {
+		a, b := tt.a.Defs[0].GoType, tt.b.Defs[0].GoType
+		if got := tt.a.Hash() == tt.b.Hash(); got != tt.want {
+			t.Errorf("Hash with a %v Symbol == Hash with a %v Symbol: %v, want %v", a, b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lang
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// DOTOptions are options for WriteDOT.
+type DOTOptions struct {
+	// Prev, if not nil, is an earlier tree of PrevLanguage, such as
+	// the input of the pass that produced the tree being drawn. Nodes
+	// that differ from the node in the same place in Prev, reached by
+	// the same fields, are highlighted: those with another name or
+	// other terminals, and those with no counterpart at all.
+	Prev         any
+	PrevLanguage *Language
+}
+
+// WriteDOT writes x, a value of l, to w as a Graphviz graph.
+// Productions and product values are nodes labeled with their names,
+// and their fields are edges labeled with the field names, except
+// that terminals, including those held by non-terminal fields, are
+// listed in the labels instead.
+func WriteDOT(w io.Writer, l *Language, x any, opts *DOTOptions) error {
+	root, err := dotTree(l, x)
+	if err != nil {
+		return err
+	}
+	if opts != nil && opts.Prev != nil {
+		prev, err := dotTree(opts.PrevLanguage, opts.Prev)
+		if err != nil {
+			return err
+		}
+		root.diff(prev)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", l.Name)
+	fmt.Fprintf(&b, "\tnode [shape=box, fontname=\"Helvetica\"];\n")
+	fmt.Fprintf(&b, "\tedge [fontname=\"Helvetica\", fontsize=10];\n")
+	if root != nil {
+		id := 0
+		root.write(&b, &id)
+	}
+	fmt.Fprintf(&b, "}\n")
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// A dotNode is a node of the graph drawn by WriteDOT.
+type dotNode struct {
+	name    string
+	lines   []string // terminal fields, as "Field: value"
+	kids    []dotEdge
+	changed bool
+}
+
+// A dotEdge is a field that leads to a node.
+type dotEdge struct {
+	label string
+	node  *dotNode
+}
+
+// dotTree returns the graph of x, a value of l, or nil if x is nil.
+func dotTree(l *Language, x any) (*dotNode, error) {
+	if x == nil {
+		return nil, nil
+	}
+	if l == nil {
+		return nil, fmt.Errorf("lang: WriteDOT: no language for %T", x)
+	}
+	v := reflect.ValueOf(x)
+	for _, def := range l.Defs {
+		if def.Kind == Product && def.GoType == v.Type() {
+			return dotFields(l, def.Name, v, def.Fields), nil
+		}
+	}
+	if n, text, ok := dotValue(l, v); ok {
+		if n == nil {
+			n = &dotNode{name: text}
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("lang: WriteDOT: %T is not a value of %v", x, l.Name)
+}
+
+// dotValue returns the node for v, a production value, or else the
+// text of v, a terminal value. It reports whether v is either.
+func dotValue(l *Language, v reflect.Value) (*dotNode, string, bool) {
+	if con := l.con(v.Type()); con != nil {
+		return dotFields(l, con.Name, v, con.Fields), "", true
+	}
+	for _, def := range l.Defs {
+		if def.Kind == Terminal && def.GoType == v.Type() {
+			return nil, fmt.Sprint(v.Interface()), true
+		}
+	}
+	return nil, "", false
+}
+
+// dotFields returns the node named name for x, a production or
+// product value with the given fields.
+func dotFields(l *Language, name string, x reflect.Value, fields []Field) *dotNode {
+	n := &dotNode{name: name}
+	for i, f := range fields {
+		n.field(l, f.Name, x.Field(i), f.Type)
+	}
+	return n
+}
+
+// field adds v, the value of type t reached by the field label, to n.
+func (n *dotNode) field(l *Language, label string, v reflect.Value, t *Type) {
+	switch t.Kind {
+	case Terminal, Int:
+		n.lines = append(n.lines, label+": "+fmt.Sprint(v.Interface()))
+	case NonTerminal:
+		if v.IsNil() {
+			n.lines = append(n.lines, label+": nil")
+			return
+		}
+		kid, text, _ := dotValue(l, v.Elem())
+		if kid == nil {
+			n.lines = append(n.lines, label+": "+text)
+			return
+		}
+		n.kids = append(n.kids, dotEdge{label, kid})
+	case Product:
+		n.kids = append(n.kids, dotEdge{label, dotFields(l, t.Name, v, l.Def(t.Name).Fields)})
+	case Slice:
+		if k := t.Elem.Kind; k == Terminal || k == Int {
+			elems := make([]string, v.Len())
+			for i := range elems {
+				elems[i] = fmt.Sprint(v.Index(i).Interface())
+			}
+			n.lines = append(n.lines, label+": ("+strings.Join(elems, " ")+")")
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			n.field(l, fmt.Sprintf("%v[%d]", label, i), v.Index(i), t.Elem)
+		}
+	case Optional:
+		if v.IsNil() {
+			n.lines = append(n.lines, label+": nil")
+			return
+		}
+		n.field(l, label, v.Elem(), t.Elem)
+	}
+}
+
+// diff marks the nodes of n that differ from those in the same place
+// in prev, which may be nil.
+func (n *dotNode) diff(prev *dotNode) {
+	n.changed = prev == nil || n.name != prev.name || !slices.Equal(n.lines, prev.lines)
+	for _, e := range n.kids {
+		var old *dotNode
+		if prev != nil {
+			for _, pe := range prev.kids {
+				if pe.label == e.label {
+					old = pe.node
+					break
+				}
+			}
+		}
+		e.node.diff(old)
+	}
+}
+
+// write writes n and its descendants to b, numbering them from *id.
+func (n *dotNode) write(b *strings.Builder, id *int) {
+	me := *id
+	*id++
+	label := dotEscape(n.name) + `\l`
+	for _, line := range n.lines {
+		label += dotEscape(line) + `\l`
+	}
+	fmt.Fprintf(b, "\tn%d [label=\"%s\"", me, label)
+	if n.changed {
+		fmt.Fprintf(b, ", style=filled, fillcolor=\"#ffd966\"")
+	}
+	fmt.Fprintf(b, "];\n")
+	for _, e := range n.kids {
+		kid := *id
+		e.node.write(b, id)
+		fmt.Fprintf(b, "\tn%d -> n%d [label=\"%s\"];\n", me, kid, dotEscape(e.label))
+	}
+}
+
+// dotEscape escapes s for a quoted DOT string.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
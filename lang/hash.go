@@ -0,0 +1,119 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lang
+
+import "strings"
+
+// A Hasher computes the 64-bit FNV-1a hash of a sequence of values,
+// for the Hash functions generated by mklang. Unlike hash/maphash, its
+// results are the same in every run, so they can be stored.
+type Hasher struct {
+	sum uint64
+}
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// NewHasher returns a new Hasher.
+func NewHasher() *Hasher {
+	return &Hasher{sum: fnvOffset}
+}
+
+func (h *Hasher) byte(c byte) {
+	h.sum ^= uint64(c)
+	h.sum *= fnvPrime
+}
+
+// Int adds x to the hash.
+func (h *Hasher) Int(x int64) {
+	h.Uint(uint64(x))
+}
+
+// Uint adds x to the hash.
+func (h *Hasher) Uint(x uint64) {
+	for i := 0; i < 8; i++ {
+		h.byte(byte(x >> (8 * i)))
+	}
+}
+
+// String adds s to the hash, prefixed by its length, so that
+// consecutive strings hash differently than their concatenation.
+func (h *Hasher) String(s string) {
+	h.Uint(uint64(len(s)))
+	for i := 0; i < len(s); i++ {
+		h.byte(s[i])
+	}
+}
+
+// Sum64 returns the hash of the values added so far.
+func (h *Hasher) Sum64() uint64 {
+	return h.sum
+}
+
+// Hash returns a hash of l's definition: its definitions, productions,
+// fields, and primitives, and the Go representations of its terminals,
+// but not the languages they came from. Two versions of a language
+// have the same hash if values of one can be read as values of the
+// other.
+func (l *Language) Hash() uint64 {
+	l.hashOnce.Do(func() {
+		h := NewHasher()
+		h.String(l.Name)
+		h.String(l.Entry)
+		h.String(l.Var)
+		h.String(l.Meta)
+		hashFields := func(fields []Field) {
+			h.Int(int64(len(fields)))
+			for _, f := range fields {
+				h.String(f.Name)
+				h.String(f.Type.String())
+				h.Int(int64(f.Type.Kind))
+				hashBool(h, f.Bind)
+				hashBool(h, f.Scope)
+			}
+		}
+		h.Int(int64(len(l.Defs)))
+		for _, def := range l.Defs {
+			h.String(def.Name)
+			h.Int(int64(def.Kind))
+			h.String(strings.Join(def.IsAlso, ","))
+			h.String(strings.Join(def.Embeds, ","))
+			if def.Kind == Terminal && def.GoType != nil {
+				// The binary encoding of a terminal depends on its
+				// representation's kind.
+				h.String(def.GoType.Kind().String())
+				h.String(def.GoType.Name())
+			}
+			h.Int(int64(len(def.Cons)))
+			for _, con := range def.Cons {
+				h.String(con.Name)
+				hashFields(con.Fields)
+			}
+			hashFields(def.Fields)
+			h.Int(int64(len(def.Prims)))
+			for _, p := range def.Prims {
+				h.String(p.Name)
+				h.Int(int64(p.Code))
+				h.Int(int64(p.Arity))
+				hashBool(h, p.Variadic)
+				h.Int(int64(p.Class))
+				hashBool(h, p.Pure)
+				hashBool(h, p.Alloc)
+			}
+		}
+		l.hash = h.Sum64()
+	})
+	return l.hash
+}
+
+func hashBool(h *Hasher, b bool) {
+	if b {
+		h.Int(1)
+	} else {
+		h.Int(0)
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lang describes the languages generated by mklang, so that
+// tools such as printers, serializers, and fuzzers can work with any
+// language without generating code of their own.
+//
+// Each generated package declares a Language variable describing
+// itself, and registers it when the package is initialized, so that
+// it can be found by the package's import path with Lookup.
+package lang
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// A Language describes a language.
+type Language struct {
+	Name  string
+	Path  string // import path of the generated package
+	Entry string // name of the entry non-terminal, if any
+	Defs  []*Def // sorted by name
+
+	// Var is the name of the terminal whose values are variables, if
+	// any fields bind them. See Field.
+	Var string
+
+	// Meta is the name of the metadata field of the language's
+	// productions and product types, if any. See Meta.
+	Meta string
+
+	once sync.Once
+	cons map[reflect.Type]*Con // productions by Go type
+
+	hashOnce sync.Once
+	hash     uint64
+}
+
+// Def returns the definition with the given name, or nil if there is
+// none.
+func (l *Language) Def(name string) *Def {
+	i, ok := slices.BinarySearchFunc(l.Defs, name, func(def *Def, name string) int {
+		return strings.Compare(def.Name, name)
+	})
+	if !ok {
+		return nil
+	}
+	return l.Defs[i]
+}
+
+// con returns the production whose Go type is t, or nil if there is
+// none.
+func (l *Language) con(t reflect.Type) *Con {
+	l.once.Do(func() {
+		l.cons = make(map[reflect.Type]*Con)
+		for _, def := range l.Defs {
+			for _, con := range def.Cons {
+				l.cons[con.GoType] = con
+			}
+		}
+	})
+	return l.cons[t]
+}
+
+// A Def describes a terminal, non-terminal, or product type.
+type Def struct {
+	Name   string
+	Kind   Kind   // Terminal, NonTerminal, or Product
+	From   string // language that introduced or last redefined it
+	GoType reflect.Type
+
+	// IsAlso lists the non-terminals, besides itself, whose values
+	// include the definition's values, by way of embeddings.
+	IsAlso []string
+
+	// Embeds lists the terminals and non-terminals that a
+	// non-terminal embeds directly.
+	Embeds []string
+
+	// Cons lists the productions of a non-terminal, sorted by name.
+	Cons []*Con
+
+	// Fields lists the fields of a product type.
+	Fields []Field
+
+	// Prims lists the primitives of a terminal declared as a
+	// primitive table, sorted by code.
+	Prims []*Prim
+}
+
+// A Con describes a production of a non-terminal.
+type Con struct {
+	Name   string
+	From   string // language that introduced or last redefined it
+	GoType reflect.Type
+	Fields []Field
+}
+
+// A Field describes a field of a production or product type.
+type Field struct {
+	Name string
+	Type *Type
+
+	// Bind reports whether the field's variables are binding
+	// occurrences, rather than references. They're binders of the
+	// production that contains the field, even if it's within a
+	// product type. For a field of a non-terminal, the binders of
+	// its value are binders of that production instead.
+	Bind bool
+
+	// Scope reports whether the field is within the scope of the
+	// binders of the production that contains it.
+	Scope bool
+}
+
+// A Type describes the type of a field.
+type Type struct {
+	Kind Kind
+	Name string // definition name, or Go type name for Int
+	Elem *Type  // element type, for Slice and Optional
+}
+
+func (t *Type) String() string {
+	switch t.Kind {
+	case Slice:
+		return "[]" + t.Elem.String()
+	case Optional:
+		return "*" + t.Elem.String()
+	}
+	return t.Name
+}
+
+// A Kind is the kind of a definition or field type.
+type Kind int
+
+const (
+	Invalid     Kind = iota
+	Terminal         // a terminal
+	NonTerminal      // a non-terminal
+	Product          // a product type
+	Int              // a Go integer type
+	Slice            // a slice of its element type
+	Optional         // a pointer to its element type, which may be nil
+)
+
+var kindNames = [...]string{
+	Invalid:     "invalid",
+	Terminal:    "terminal",
+	NonTerminal: "non-terminal",
+	Product:     "product",
+	Int:         "int",
+	Slice:       "slice",
+	Optional:    "optional",
+}
+
+func (k Kind) String() string {
+	if 0 <= k && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// A Prim describes a primitive operation: one of the values of a
+// terminal declared as a primitive table.
+type Prim struct {
+	Name     string // as written in s-expressions
+	Code     int    // the terminal's value; the same in every language
+	Arity    int    // number of arguments, or the minimum if Variadic
+	Variadic bool
+	Class    PrimClass
+	Pure     bool // free of side effects
+	Alloc    bool // allocates memory
+}
+
+// A PrimClass classifies a primitive by the context it's used in.
+type PrimClass int
+
+const (
+	Value     PrimClass = iota + 1 // produces a value
+	Effect                         // is used only for its side effects
+	Predicate                      // produces a boolean, for use in tests
+)
+
+var primClassNames = [...]string{
+	Value:     "value",
+	Effect:    "effect",
+	Predicate: "predicate",
+}
+
+func (c PrimClass) String() string {
+	if 0 < c && int(c) < len(primClassNames) {
+		return primClassNames[c]
+	}
+	return fmt.Sprintf("PrimClass(%d)", int(c))
+}
+
+var (
+	mu        sync.RWMutex
+	languages = make(map[string]*Language)
+)
+
+// Register makes a language available by its import path, so that
+// languages with the same name, from different compilers, can be
+// registered together. If Register is called twice with the same
+// path, it panics.
+func Register(l *Language) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, dup := languages[l.Path]; dup {
+		panic("lang: Register called twice for language " + l.Path)
+	}
+	languages[l.Path] = l
+}
+
+// Lookup returns the registered language with the given import path,
+// or nil if there is none.
+func Lookup(path string) *Language {
+	mu.RLock()
+	defer mu.RUnlock()
+	return languages[path]
+}
+
+// Languages returns a sorted list of the import paths of the
+// registered languages.
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	res := make([]string, 0, len(languages))
+	for path := range languages {
+		res = append(res, path)
+	}
+	slices.Sort(res)
+	return res
+}
@@ -0,0 +1,36 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lang
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	// Two compilers can each have a language L1.
+	a := &Language{Name: "L1", Path: "example.com/a/L1"}
+	b := &Language{Name: "L1", Path: "example.com/b/L1"}
+	Register(a)
+	Register(b)
+	for _, l := range []*Language{a, b} {
+		if got := Lookup(l.Path); got != l {
+			t.Errorf("Lookup(%q) = %p, want %p", l.Path, got, l)
+		}
+		if !slices.Contains(Languages(), l.Path) {
+			t.Errorf("Languages() = %q, missing %q", Languages(), l.Path)
+		}
+	}
+	if got := Lookup("L1"); got != nil {
+		t.Errorf("Lookup(%q) = %p, want nil", "L1", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering %v twice did not panic", a.Path)
+		}
+	}()
+	Register(&Language{Name: "L2", Path: a.Path})
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lang
+
+import (
+	"reflect"
+
+	"github.com/mdempsky/hermes/sexpr"
+)
+
+// Meta is the metadata of a node, for languages that declare a
+// metadata field: mklang adds it to every production and product
+// type. It holds the span of source text that the node was read from,
+// if any, and an annotation of type A for passes to use.
+//
+// Metadata isn't part of a node's structure: Equal, Hash, and Unparse
+// ignore it.
+type Meta[A any] struct {
+	Span Span
+	Note A
+}
+
+// Same reports whether m and o certainly hold the same metadata: the
+// same span, and annotations that are comparable and equal. It's
+// false for annotations that can't be compared, such as slices.
+func (m Meta[A]) Same(o Meta[A]) bool {
+	if m.Span != o.Span {
+		return false
+	}
+	a, b := reflect.ValueOf(&m.Note).Elem(), reflect.ValueOf(&o.Note).Elem()
+	return a.Comparable() && b.Comparable() && a.Equal(b)
+}
+
+// A Span is a range of source text. The zero Span is unknown.
+type Span struct {
+	Start, End sexpr.Pos // End is just after the last character
+}
+
+// SpanOf returns the span of x, an s-expression produced by
+// sexpr.Parse.
+func SpanOf(x sexpr.Expr) Span {
+	return Span{Start: x.Pos(), End: x.End()}
+}
+
+// IsKnown reports whether s is known.
+func (s Span) IsKnown() bool { return s.Start.IsKnown() }
+
+func (s Span) String() string {
+	if !s.IsKnown() {
+		return "-"
+	}
+	return s.Start.String() + "-" + s.End.String()
+}
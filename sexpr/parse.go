@@ -0,0 +1,144 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sexpr
+
+import "fmt"
+
+// A Pos is a position within s-expression source text. Lines and
+// columns are numbered from 1; the zero Pos is unknown.
+type Pos struct {
+	Line, Col int
+}
+
+func (pos Pos) IsKnown() bool { return pos.Line > 0 }
+
+func (pos Pos) String() string {
+	if !pos.IsKnown() {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Col)
+}
+
+// An Error is an error at a position within s-expression source text.
+type Error struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Pos, e.Msg)
+}
+
+// Errorf returns an *Error at the position of x.
+func Errorf(x Expr, format string, args ...any) *Error {
+	return &Error{Pos: x.Pos(), Msg: fmt.Sprintf(format, args...)}
+}
+
+// Parse parses src, which must contain exactly one s-expression.
+// Comments start with a semicolon and extend to the end of the line.
+func Parse(src string) (Expr, error) {
+	p := parser{src: src, line: 1, col: 1}
+	x, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skip(); p.off < len(p.src) {
+		return nil, p.errorf("unexpected %q after expression", p.src[p.off])
+	}
+	return x, nil
+}
+
+type parser struct {
+	src       string
+	off       int
+	line, col int
+}
+
+func (p *parser) pos() Pos { return Pos{p.line, p.col} }
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &Error{Pos: p.pos(), Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) next() {
+	if p.src[p.off] == '\n' {
+		p.line++
+		p.col = 1
+	} else {
+		p.col++
+	}
+	p.off++
+}
+
+// skip advances past any whitespace and comments.
+func (p *parser) skip() {
+	for p.off < len(p.src) {
+		switch c := p.src[p.off]; {
+		case c == ';':
+			for p.off < len(p.src) && p.src[p.off] != '\n' {
+				p.next()
+			}
+		case isSpace(c):
+			p.next()
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) expr() (Expr, error) {
+	p.skip()
+	if p.off == len(p.src) {
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	pos := p.pos()
+	switch c := p.src[p.off]; c {
+	case '(', '[':
+		p.next()
+		close := byte(')')
+		if c == '[' {
+			close = ']'
+		}
+
+		l := &List{Brack: c == '[', pos: pos}
+		for {
+			p.skip()
+			if p.off == len(p.src) {
+				return nil, &Error{Pos: pos, Msg: fmt.Sprintf("unclosed %q", c)}
+			}
+			if c := p.src[p.off]; c == ')' || c == ']' {
+				if c != close {
+					return nil, p.errorf("unexpected %q, expected %q", c, close)
+				}
+				p.next()
+				l.end = p.pos()
+				return l, nil
+			}
+			elem, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			l.Elems = append(l.Elems, elem)
+		}
+
+	case ')', ']':
+		return nil, p.errorf("unexpected %q", c)
+	}
+
+	start := p.off
+	for p.off < len(p.src) && !isDelim(p.src[p.off]) {
+		p.next()
+	}
+	return &Atom{Text: p.src[start:p.off], pos: pos, end: p.pos()}, nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDelim(c byte) bool {
+	return isSpace(c) || c == '(' || c == ')' || c == '[' || c == ']' || c == ';'
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sexpr implements the s-expression notation used by the
+// Unparse, Format, and Parse functions that mklang generates for each
+// language.
+package sexpr
+
+import (
+	"io"
+	"strings"
+	"unicode"
+)
+
+// An Expr is an s-expression: either an *Atom or a *List.
+type Expr interface {
+	// Pos returns the position of the first character of the
+	// expression, if it was produced by Parse.
+	Pos() Pos
+
+	// End returns the position just after the last character of the
+	// expression, if it was produced by Parse.
+	End() Pos
+
+	String() string
+	isExpr()
+}
+
+// An Atom is an unbroken sequence of non-delimiter characters, such
+// as a symbol or an integer.
+type Atom struct {
+	Text string
+
+	pos, end Pos
+}
+
+// A List is a sequence of s-expressions, delimited by parentheses or,
+// if Brack is set, by square brackets.
+type List struct {
+	Brack bool
+	Elems []Expr
+
+	pos, end Pos
+}
+
+func (*Atom) isExpr() {}
+func (*List) isExpr() {}
+
+func (x *Atom) Pos() Pos { return x.pos }
+func (x *List) Pos() Pos { return x.pos }
+
+func (x *Atom) End() Pos { return x.end }
+func (x *List) End() Pos { return x.end }
+
+func (x *Atom) String() string { return x.Text }
+
+func (x *List) String() string {
+	var b strings.Builder
+	flat(&b, x)
+	return b.String()
+}
+
+func (x *List) delims() (open, close string) {
+	if x.Brack {
+		return "[", "]"
+	}
+	return "(", ")"
+}
+
+// Fold returns s in the canonical form used for comparing list
+// heads: lowercased, and without any characters other than letters
+// and digits. For example, "Set", "set", and "set!" all fold to
+// "set".
+func Fold(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+func flat(b *strings.Builder, x Expr) {
+	switch x := x.(type) {
+	case *Atom:
+		b.WriteString(x.Text)
+	case *List:
+		open, close := x.delims()
+		b.WriteString(open)
+		for i, elem := range x.Elems {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			flat(b, elem)
+		}
+		b.WriteString(close)
+	}
+}
+
+// fits reports whether x can be printed on a single line within
+// room columns.
+func fits(x Expr, room int) bool {
+	return width(x, room) <= room
+}
+
+// width returns the single-line width of x, or some value greater
+// than limit if that width exceeds limit.
+func width(x Expr, limit int) int {
+	switch x := x.(type) {
+	case *Atom:
+		return len(x.Text)
+	case *List:
+		n := 2 + max(len(x.Elems)-1, 0)
+		for _, elem := range x.Elems {
+			if n > limit {
+				break
+			}
+			n += width(elem, limit-n)
+		}
+		return n
+	}
+	panic("unreachable")
+}
+
+// Fprint writes x to w. Lists that do not fit on a single line within
+// width columns are broken across lines: a list headed by an atom
+// keeps its first argument on the head's line and indents the
+// remaining elements by two columns; any other list aligns its
+// elements beneath the first.
+func Fprint(w io.Writer, x Expr, width int) error {
+	p := printer{width: width}
+	p.print(x)
+	_, err := io.WriteString(w, p.buf.String())
+	return err
+}
+
+// Sprint is like Fprint, but returns the result as a string.
+func Sprint(x Expr, width int) string {
+	p := printer{width: width}
+	p.print(x)
+	return p.buf.String()
+}
+
+type printer struct {
+	buf   strings.Builder
+	width int
+	col   int
+}
+
+func (p *printer) write(s string) {
+	p.buf.WriteString(s)
+	p.col += len(s)
+}
+
+func (p *printer) newline(indent int) {
+	p.buf.WriteByte('\n')
+	p.buf.WriteString(strings.Repeat(" ", indent))
+	p.col = indent
+}
+
+func (p *printer) print(x Expr) {
+	l, ok := x.(*List)
+	if !ok || len(l.Elems) == 0 || fits(x, p.width-p.col) {
+		p.write(x.String())
+		return
+	}
+
+	start := p.col
+	open, close := l.delims()
+	p.write(open)
+
+	elems := l.Elems
+	indent := p.col
+	if _, ok := elems[0].(*Atom); ok && len(elems) > 1 {
+		p.write(elems[0].String())
+		p.write(" ")
+		elems = elems[1:]
+		indent = start + 2
+	}
+
+	p.print(elems[0])
+	for _, elem := range elems[1:] {
+		p.newline(indent)
+		p.print(elem)
+	}
+	p.write(close)
+}
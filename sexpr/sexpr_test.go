@@ -0,0 +1,57 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sexpr
+
+import "testing"
+
+func TestSprint(t *testing.T) {
+	tests := []struct {
+		src   string
+		width int
+		want  string
+	}{
+		{"(if c t e)", 80, "(if c t e)"},
+		{"(let ([x e]) body)", 80, "(let ([x e]) body)"},
+		{"(if c t e)", 8, "(if c\n  t\n  e)"},
+		{
+			"(let ([x (f a b)] [y (g c d)]) (+ x y))",
+			20,
+			"(let ([x (f a b)]\n      [y (g c d)])\n  (+ x y))",
+		},
+		{
+			"((lambda (x) x) (f a b c d))",
+			20,
+			"((lambda (x) x)\n (f a b c d))",
+		},
+		{"(a-very-long-atom)", 4, "(a-very-long-atom)"},
+		{"()", 1, "()"},
+	}
+	for _, tt := range tests {
+		x, err := Parse(tt.src)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.src, err)
+			continue
+		}
+		if got := Sprint(x, tt.width); got != tt.want {
+			t.Errorf("Sprint(%q, %d) =\n%s\nwant\n%s", tt.src, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct{ s, want string }{
+		{"set", "set"},
+		{"Set", "set"},
+		{"set!", "set"},
+		{"primcall", "primcall"},
+		{"letrec*", "letrec"},
+		{"lambda-expr", "lambdaexpr"},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.s); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}